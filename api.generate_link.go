@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type ApiGenerateLinkResponse struct {
@@ -26,17 +25,10 @@ func newApiGenerateLinkResult(result ApiGenerateLinkResponse, body []byte, http
 // https://union.meituan.com/v2/apiDetail?id=25
 func (c *Client) ApiGenerateLink(ctx context.Context, notMustParams ...gorequest.Params) (*ApiGenerateLinkResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "api/generateLink")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
-	params.Set("appkey", c.GetAppKey()) // 媒体名称，可在推广者备案-媒体管理中查询
-	params.Set("sign", c.getSign(c.GetSecret(), params))
 
 	// 请求
-	var response ApiGenerateLinkResponse
-	request, err := c.request(ctx, "api/generateLink", params, http.MethodGet, &response)
+	response, request, err := call[ApiGenerateLinkResponse](ctx, c, endpointApiGenerateLink, params)
 	return newApiGenerateLinkResult(response, request.ResponseBody, request), err
 }
@@ -3,8 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"go.dtapp.net/gotime"
-	"net/http"
 )
 
 type ApiGetQuaLitYsCoreBySidResponse struct {
@@ -35,19 +33,10 @@ func newApiGetQuaLitYsCoreBySidResult(result ApiGetQuaLitYsCoreBySidResponse, bo
 // https://union.meituan.com/v2/apiDetail?id=28
 func (c *Client) ApiGetQuaLitYsCoreBySid(ctx context.Context, notMustParams ...gorequest.Params) (*ApiGetQuaLitYsCoreBySidResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "api/getqualityscorebysid")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
-	// 请求时刻10位时间戳(秒级)，有效期60s
-	params.Set("ts", gotime.Current().Timestamp())
-	params.Set("appkey", c.GetAppKey())
-	params.Set("sign", c.getSign(c.GetSecret(), params))
 
 	// 请求
-	var response ApiGetQuaLitYsCoreBySidResponse
-	request, err := c.request(ctx, "api/getqualityscorebysid", params, http.MethodGet, &response)
+	response, request, err := call[ApiGetQuaLitYsCoreBySidResponse](ctx, c, endpointApiGetQuaLitYsCoreBySid, params)
 	return newApiGetQuaLitYsCoreBySidResult(response, request.ResponseBody, request), err
 }
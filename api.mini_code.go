@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type ApiMiniCodeResponse struct {
@@ -26,17 +25,10 @@ func newApiMiniCodeResult(result ApiMiniCodeResponse, body []byte, http goreques
 // https://union.meituan.com/v2/apiDetail?id=26
 func (c *Client) ApiMiniCode(ctx context.Context, notMustParams ...gorequest.Params) (*ApiMiniCodeResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "api/miniCode")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
-	params.Set("appkey", c.GetAppKey()) // 媒体名称，可在推广者备案-媒体管理中查询
-	params.Set("sign", c.getSign(c.GetSecret(), params))
 
 	// 请求
-	var response ApiMiniCodeResponse
-	request, err := c.request(ctx, "api/miniCode", params, http.MethodGet, &response)
+	response, request, err := call[ApiMiniCodeResponse](ctx, c, endpointApiMiniCode, params)
 	return newApiMiniCodeResult(response, request.ResponseBody, request), err
 }
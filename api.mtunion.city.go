@@ -3,8 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"go.dtapp.net/gotime"
-	"net/http"
 )
 
 type ApiMtUnionCityResponse struct {
@@ -32,19 +30,10 @@ func newApiMtUnionCityResult(result ApiMtUnionCityResponse, body []byte, http go
 // https://union.meituan.com/v2/apiDetail?id=29
 func (c *Client) ApiMtUnionCity(ctx context.Context, notMustParams ...gorequest.Params) (*ApiMtUnionCityResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "api/getqualityscorebysid")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
-	// 请求时刻10位时间戳(秒级)，有效期60s
-	params.Set("ts", gotime.Current().Timestamp())
-	params.Set("appkey", c.GetAppKey())
-	params.Set("sign", c.getSign(c.GetSecret(), params))
 
 	// 请求
-	var response ApiMtUnionCityResponse
-	request, err := c.request(ctx, "api/getqualityscorebysid", params, http.MethodGet, &response)
+	response, request, err := call[ApiMtUnionCityResponse](ctx, c, endpointApiMtUnionCity, params)
 	return newApiMtUnionCityResult(response, request.ResponseBody, request), err
 }
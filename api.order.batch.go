@@ -0,0 +1,86 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gorequest"
+	"sync"
+)
+
+// GetOrdersOptions 批量查询订单配置
+type GetOrdersOptions struct {
+	Concurrency  int              // 同时查询的订单数量，默认4
+	BusinessLine BusinessLine     // 业务线，为0时不传
+	Params       gorequest.Params // 每个订单查询附加的参数
+}
+
+// GetOrdersResult 单个订单的查询结果
+type GetOrdersResult struct {
+	OrderID string          // 订单id
+	Result  *ApiOrderResult // 接口返回
+	Order   Order           // 转换后的订单
+	Err     error           // 查询或转换失败的错误
+}
+
+// GetOrders 批量查询订单，按 Concurrency 并发调用单订单查询接口，请求受客户端限流控制
+// 返回结果与 ids 顺序一致，单个订单失败只记录在对应结果的 Err 中，不影响其他订单
+func (c *Client) GetOrders(ctx context.Context, ids []string, opts GetOrdersOptions) []GetOrdersResult {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+
+	results := make([]GetOrdersResult, len(ids))
+	for i, id := range ids {
+		results[i].OrderID = id
+	}
+
+	var wg sync.WaitGroup
+	jobs := make(chan int)
+	for i := 0; i < opts.Concurrency && i < len(ids); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				// 取消后仍可能取到任务，不再发送请求
+				if ctx.Err() != nil {
+					continue
+				}
+				c.getOrder(ctx, opts, &results[index])
+			}
+		}()
+	}
+
+dispatch:
+	for i := range ids {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// 上下文取消后没有查询的订单
+	if err := ctx.Err(); err != nil {
+		for i := range results {
+			if results[i].Result == nil && results[i].Err == nil {
+				results[i].Err = err
+			}
+		}
+	}
+	return results
+}
+
+// 查询一个订单
+func (c *Client) getOrder(ctx context.Context, opts GetOrdersOptions, result *GetOrdersResult) {
+	params := gorequest.NewParamsWith(opts.Params)
+	params.Set("orderId", result.OrderID)
+	if opts.BusinessLine != 0 {
+		params.Set("businessLine", int(opts.BusinessLine))
+	}
+	result.Result, result.Err = c.ApiOrder(ctx, params)
+	if result.Err != nil {
+		return
+	}
+	result.Order, result.Err = result.Result.Result.Order()
+}
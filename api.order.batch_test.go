@@ -0,0 +1,133 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 单订单查询测试服务，返回请求的订单id
+func orderQueryHandler(f func(w http.ResponseWriter, r *http.Request, orderID string) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		orderID := r.URL.Query().Get("orderId")
+		if f != nil && !f(w, r, orderID) {
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":0,"des":"成功","data":{"orderId":"` + orderID + `","status":1,"payprice":"10.00"}}`))
+	})
+}
+
+func TestGetOrders(t *testing.T) {
+	c := newTestClient(t, orderQueryHandler(func(w http.ResponseWriter, r *http.Request, orderID string) bool {
+		switch orderID {
+		case "business":
+			_, _ = w.Write([]byte(`{"status":1,"des":"订单不存在"}`))
+			return false
+		case "server":
+			w.WriteHeader(http.StatusInternalServerError)
+			return false
+		case "invalid":
+			_, _ = w.Write([]byte(`{"status":0,"data":{"orderId":"invalid","payprice":"x"}}`))
+			return false
+		}
+		if r.URL.Query().Get("businessLine") != "4" || r.URL.Query().Get("extra") != "1" {
+			t.Errorf("请求参数 %s", r.URL.RawQuery)
+		}
+		return true
+	}))
+
+	ids := []string{"1", "business", "2", "server", "invalid", "3"}
+	results := c.GetOrders(context.Background(), ids, GetOrdersOptions{
+		Concurrency:  3,
+		BusinessLine: BusinessLineWaimai,
+		Params:       map[string]any{"extra": 1},
+	})
+	if len(results) != len(ids) {
+		t.Fatalf("结果 %d 个", len(results))
+	}
+	// 结果与 ids 顺序一致，单个订单失败不影响其他订单
+	for i, result := range results {
+		if result.OrderID != ids[i] {
+			t.Errorf("第%d个结果的订单id %s, want %s", i+1, result.OrderID, ids[i])
+		}
+		switch ids[i] {
+		case "business", "server", "invalid":
+			if result.Err == nil {
+				t.Errorf("订单 %s 应返回错误", ids[i])
+			}
+		default:
+			if result.Err != nil || result.Order.OrderID != ids[i] || result.Order.PayPrice != 1000 {
+				t.Errorf("订单 %s 结果 %+v", ids[i], result)
+			}
+		}
+	}
+	var apiErr *APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.Code != 1 {
+		t.Errorf("业务错误 %v", results[1].Err)
+	}
+	if !errors.As(results[3].Err, &apiErr) || apiErr.HttpStatusCode != http.StatusInternalServerError {
+		t.Errorf("服务端异常 %v", results[3].Err)
+	}
+}
+
+func TestGetOrdersConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	c := newTestClient(t, orderQueryHandler(func(w http.ResponseWriter, r *http.Request, orderID string) bool {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return true
+	}))
+
+	ids := make([]string, 12)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	for _, result := range c.GetOrders(context.Background(), ids, GetOrdersOptions{Concurrency: 3}) {
+		if result.Err != nil {
+			t.Errorf("订单 %s: %v", result.OrderID, result.Err)
+		}
+	}
+	if p := peak.Load(); p > 3 || p < 2 {
+		t.Errorf("最大并发 %d, want <= 3", p)
+	}
+}
+
+func TestGetOrdersCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var requests atomic.Int32
+	c := newTestClient(t, orderQueryHandler(func(w http.ResponseWriter, r *http.Request, orderID string) bool {
+		// 第一个请求期间取消
+		if requests.Add(1) == 1 {
+			cancel()
+			<-r.Context().Done()
+			return false
+		}
+		return true
+	}))
+
+	ids := []string{"1", "2", "3", "4"}
+	results := c.GetOrders(ctx, ids, GetOrdersOptions{Concurrency: 1})
+	if n := requests.Load(); n != 1 {
+		t.Errorf("请求 %d 次, want 1", n)
+	}
+	for i, result := range results {
+		if result.OrderID != ids[i] || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("第%d个结果 %+v", i+1, result)
+		}
+		if i > 0 && result.Result != nil {
+			t.Errorf("未发送的订单 %s 有接口返回", result.OrderID)
+		}
+	}
+}
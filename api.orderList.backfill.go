@@ -0,0 +1,154 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BackfillOptions 订单回补配置
+type BackfillOptions struct {
+	Request       ApiOrderListRequest    // 查询条件，StartTime 和 EndTime 为整个回补范围，可以超过 ApiOrderListMaxWindow
+	Window        time.Duration          // 每个时间窗口的长度，默认且最大为 ApiOrderListMaxWindow
+	Concurrency   int                    // 同时查询的时间窗口数量，默认4
+	WindowRetries int                    // 时间窗口查询失败后的重试次数，默认3
+	OnProgress    func(BackfillProgress) // 进度回调，每个时间窗口完成或失败时调用，不会并发调用
+}
+
+// BackfillProgress 订单回补进度
+type BackfillProgress struct {
+	StartTime    time.Time // 时间窗口起始时间
+	EndTime      time.Time // 时间窗口截止时间
+	Orders       int       // 时间窗口的订单数量
+	Attempts     int       // 时间窗口的查询次数
+	Err          error     // 时间窗口重试后仍然失败的错误
+	Completed    int       // 已完成的时间窗口数量
+	TotalWindows int       // 时间窗口总数
+}
+
+// 回补时间窗口
+type backfillWindow struct {
+	index int
+	start time.Time
+	end   time.Time
+}
+
+// 按时间窗口长度切分回补范围
+func splitBackfillWindows(start, end time.Time, window time.Duration) []backfillWindow {
+	var windows []backfillWindow
+	for from := start; from.Before(end); from = from.Add(window) {
+		to := from.Add(window)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, backfillWindow{index: len(windows), start: from, end: to})
+	}
+	return windows
+}
+
+// BackfillOrders 回补一段时间内的订单
+// 将时间范围切分为接口允许的时间窗口，并发查询每个时间窗口的所有分页，相邻时间窗口重复返回的订单按订单id去重，保留较晚时间窗口返回的订单
+// 时间窗口失败时单独重试，重试后仍然失败的时间窗口通过返回的错误汇总，已获取的订单仍然返回
+func (c *Client) BackfillOrders(ctx context.Context, opts BackfillOptions) ([]ApiOrderListOrder, error) {
+	if opts.Window <= 0 || opts.Window > ApiOrderListMaxWindow {
+		opts.Window = ApiOrderListMaxWindow
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.WindowRetries < 0 {
+		opts.WindowRetries = 0
+	} else if opts.WindowRetries == 0 {
+		opts.WindowRetries = 3
+	}
+	if opts.Request.StartTime.IsZero() || !opts.Request.EndTime.After(opts.Request.StartTime) {
+		return nil, fmt.Errorf("%w: 回补截止时间必须晚于起始时间", ErrInvalidRequest)
+	}
+
+	windows := splitBackfillWindows(opts.Request.StartTime, opts.Request.EndTime, opts.Window)
+	results := make([][]ApiOrderListOrder, len(windows))
+	errs := make([]error, len(windows))
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed int
+	)
+	jobs := make(chan backfillWindow)
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w := range jobs {
+				orders, attempts, err := c.backfillWindow(ctx, opts, w)
+				results[w.index], errs[w.index] = orders, err
+
+				mu.Lock()
+				completed++
+				if opts.OnProgress != nil {
+					opts.OnProgress(BackfillProgress{
+						StartTime:    w.start,
+						EndTime:      w.end,
+						Orders:       len(orders),
+						Attempts:     attempts,
+						Err:          err,
+						Completed:    completed,
+						TotalWindows: len(windows),
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, w := range windows {
+		select {
+		case jobs <- w:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// 相邻时间窗口的边界订单会重复返回，较晚的时间窗口返回的订单状态更新，位置不变
+	seen := make(map[string]int)
+	var orders []ApiOrderListOrder
+	for _, windowOrders := range results {
+		for _, order := range windowOrders {
+			if i, ok := seen[order.Orderid]; ok {
+				orders[i] = order
+				continue
+			}
+			seen[order.Orderid] = len(orders)
+			orders = append(orders, order)
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return orders, err
+	}
+	return orders, errors.Join(errs...)
+}
+
+// 查询一个时间窗口的所有分页，失败时整个时间窗口重试
+func (c *Client) backfillWindow(ctx context.Context, opts BackfillOptions, w backfillWindow) (orders []ApiOrderListOrder, attempts int, err error) {
+	req := opts.Request
+	req.StartTime, req.EndTime = w.start, w.end
+	for attempts = 1; ; attempts++ {
+		orders, err = c.CollectOrders(ctx, req)
+		if err == nil || attempts > opts.WindowRetries || ctx.Err() != nil {
+			break
+		}
+		if sleepContext(ctx, c.retry.backoff(attempts)) != nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, attempts, fmt.Errorf("回补时间窗口 %s - %s 失败: %w", w.start.Format(time.DateTime), w.end.Format(time.DateTime), err)
+	}
+	return orders, attempts, nil
+}
@@ -0,0 +1,145 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSplitBackfillWindows(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	windows := splitBackfillWindows(start, start.Add(5*time.Hour), 2*time.Hour)
+	want := []backfillWindow{
+		{index: 0, start: start, end: start.Add(2 * time.Hour)},
+		{index: 1, start: start.Add(2 * time.Hour), end: start.Add(4 * time.Hour)},
+		{index: 2, start: start.Add(4 * time.Hour), end: start.Add(5 * time.Hour)},
+	}
+	if !reflect.DeepEqual(windows, want) {
+		t.Errorf("时间窗口 %v, want %v", windows, want)
+	}
+	if windows = splitBackfillWindows(start, start.Add(4*time.Hour), 2*time.Hour); len(windows) != 2 {
+		t.Errorf("整除时的时间窗口 %v", windows)
+	}
+}
+
+func TestBackfillOrders(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	windowStart := func(i int) string { return strconv.FormatInt(start.Add(time.Duration(i)*2*time.Hour).Unix(), 10) }
+	// 相邻时间窗口的边界订单重复返回，第2个时间窗口第一次查询失败
+	windowOrders := map[string][]string{
+		windowStart(0): {"1", "2"},
+		windowStart(1): {"2", "3"},
+		windowStart(2): {"3", "4"},
+	}
+	var (
+		mu       sync.Mutex
+		requests = make(map[string]int)
+	)
+	c := newTestClient(t, &orderListStub{response: func(r *http.Request, page int) ([]string, int) {
+		from := r.URL.Query().Get("startTime")
+		mu.Lock()
+		requests[from]++
+		n := requests[from]
+		mu.Unlock()
+		if from == windowStart(1) && n == 1 {
+			return nil, -1
+		}
+		return windowOrders[from], len(windowOrders[from])
+	}})
+	c.retry = RetryConfig{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}.withDefaults()
+
+	var progress []BackfillProgress
+	orders, err := c.BackfillOrders(context.Background(), BackfillOptions{
+		Request:     ApiOrderListRequest{StartTime: start, EndTime: start.Add(5 * time.Hour)},
+		Window:      2 * time.Hour,
+		Concurrency: 2,
+		OnProgress:  func(p BackfillProgress) { progress = append(progress, p) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 重复的订单保留较晚时间窗口返回的
+	if ids := orderListIDs(orders); !reflect.DeepEqual(ids, []string{"1", "2", "3", "4"}) {
+		t.Errorf("订单 %v", ids)
+	}
+	wantFrom := []string{windowStart(0), windowStart(1), windowStart(2), windowStart(2)}
+	for i, order := range orders {
+		if order.Paytime != wantFrom[i] {
+			t.Errorf("订单 %s 来自起始时间 %s 的时间窗口, want %s", order.Orderid, order.Paytime, wantFrom[i])
+		}
+	}
+
+	if requests[windowStart(0)] != 1 || requests[windowStart(1)] != 2 || requests[windowStart(2)] != 1 {
+		t.Errorf("时间窗口请求次数 %v", requests)
+	}
+	if len(progress) != 3 {
+		t.Fatalf("进度回调 %d 次", len(progress))
+	}
+	for i, p := range progress {
+		if p.Completed != i+1 || p.TotalWindows != 3 || p.Err != nil || p.Orders != 2 {
+			t.Errorf("第%d次进度 %+v", i+1, p)
+		}
+		wantAttempts := 1
+		if p.StartTime.Equal(start.Add(2 * time.Hour)) {
+			wantAttempts = 2
+		}
+		if p.Attempts != wantAttempts {
+			t.Errorf("时间窗口 %v 查询 %d 次, want %d", p.StartTime, p.Attempts, wantAttempts)
+		}
+	}
+}
+
+func TestBackfillOrdersWindowFailure(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	failed := strconv.FormatInt(start.Add(time.Hour).Unix(), 10)
+	var (
+		mu       sync.Mutex
+		attempts int
+	)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("startTime") == failed {
+			mu.Lock()
+			attempts++
+			mu.Unlock()
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"dataList":[{"orderid":"1"}],"total":1}`))
+	}))
+	c.retry = RetryConfig{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}.withDefaults()
+
+	var progress []BackfillProgress
+	orders, err := c.BackfillOrders(context.Background(), BackfillOptions{
+		Request:       ApiOrderListRequest{StartTime: start, EndTime: start.Add(2 * time.Hour)},
+		Window:        time.Hour,
+		WindowRetries: 2,
+		OnProgress:    func(p BackfillProgress) { progress = append(progress, p) },
+	})
+	// 失败的时间窗口汇总到错误中，其他时间窗口的订单仍然返回
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HttpStatusCode != http.StatusInternalServerError {
+		t.Errorf("err = %v", err)
+	}
+	if len(orders) != 1 {
+		t.Errorf("订单 %v", orders)
+	}
+	if attempts != 3 {
+		t.Errorf("失败的时间窗口请求 %d 次, want 3", attempts)
+	}
+	for _, p := range progress {
+		if failed := p.StartTime.Equal(start.Add(time.Hour)); failed != (p.Err != nil) || (failed && p.Attempts != 3) {
+			t.Errorf("进度 %+v", p)
+		}
+	}
+
+	// 截止时间早于起始时间
+	if _, err = c.BackfillOrders(context.Background(), BackfillOptions{Request: ApiOrderListRequest{StartTime: start, EndTime: start}}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package meituan
+
+import (
+	"bytes"
+	"fmt"
+	"go.dtapp.net/gojson"
+	"strconv"
+)
+
+// ApiOrderListExtra 订单扩展信息，与单订单查询接口的同名字段一致
+type ApiOrderListExtra struct {
+	Quantity          int                     `json:"quantity,omitempty"`          // 商品数量
+	CouponCode        string                  `json:"coupon_code,omitempty"`       // 券码
+	RefundInfoList    []ApiOrderRefundInfo    `json:"refundInfoList,omitempty"`    // 退款列表
+	RefundProfitList  []ApiOrderRefundProfit  `json:"refundProfitList,omitempty"`  // 退款佣金列表
+	ConsumeProfitList []ApiOrderConsumeProfit `json:"consumeProfitList,omitempty"` // 核销佣金列表
+}
+
+// ExtraInfo 解析订单扩展信息，未列出的字段使用 ExtraMap 或 DecodeExtra 获取，extra 为空时返回零值
+func (o ApiOrderListOrder) ExtraInfo() (ApiOrderListExtra, error) {
+	var extra ApiOrderListExtra
+	err := o.DecodeExtra(&extra)
+	return extra, err
+}
+
+// ExtraMap 解析订单扩展信息，extra 为空时返回 nil
+func (o ApiOrderListOrder) ExtraMap() (map[string]any, error) {
+	var extra map[string]any
+	if err := o.DecodeExtra(&extra); err != nil {
+		return nil, err
+	}
+	return extra, nil
+}
+
+// DecodeExtra 将订单扩展信息解析到 v，extra 为空时不做任何处理
+func (o ApiOrderListOrder) DecodeExtra(v any) error {
+	if o.Extra == "" {
+		return nil
+	}
+	if err := gojson.Unmarshal([]byte(o.Extra), v); err != nil {
+		return fmt.Errorf("订单扩展信息解析失败: %w", err)
+	}
+	return nil
+}
+
+// TradeTypeBusinessTypes 解析奖励类型对应的业务类型，键为奖励类型
+func (o ApiOrderListOrder) TradeTypeBusinessTypes() (map[TradeType][]int, error) {
+	if o.TradeTypeBusinessTypeMapStr == "" {
+		return nil, nil
+	}
+	var raw map[string]businessTypeList
+	if err := gojson.Unmarshal([]byte(o.TradeTypeBusinessTypeMapStr), &raw); err != nil {
+		return nil, fmt.Errorf("奖励类型对应业务类型解析失败: %w", err)
+	}
+	result := make(map[TradeType][]int, len(raw))
+	for k, v := range raw {
+		tradeType, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, fmt.Errorf("奖励类型对应业务类型解析失败: %w", err)
+		}
+		result[TradeType(tradeType)] = v
+	}
+	return result, nil
+}
+
+// 业务类型列表，兼容单个值、数字字符串和数组
+type businessTypeList []int
+
+func (l *businessTypeList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*l = nil
+		return nil
+	}
+	if len(data) > 0 && data[0] == '[' {
+		var items []businessTypeList
+		if err := gojson.Unmarshal(data, &items); err != nil {
+			return err
+		}
+		list := make(businessTypeList, 0, len(items))
+		for _, item := range items {
+			list = append(list, item...)
+		}
+		*l = list
+		return nil
+	}
+	s := string(bytes.Trim(data, `"`))
+	if s == "" {
+		*l = nil
+		return nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("业务类型格式错误: %s", data)
+	}
+	*l = businessTypeList{i}
+	return nil
+}
@@ -0,0 +1,105 @@
+package meituan
+
+import (
+	"context"
+)
+
+// OrderIterator 订单列表分页迭代器，按需逐页请求订单列表查询接口
+//
+//	it := c.IterateOrders(ctx, req)
+//	defer it.Close()
+//	for it.Next() {
+//		order := it.Order()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type OrderIterator struct {
+	ctx     context.Context
+	c       *Client
+	req     ApiOrderListRequest
+	page    []ApiOrderListOrder // 当前页
+	index   int                 // 当前页的下一条位置
+	fetched int                 // 已获取的条数
+	current ApiOrderListOrder   // 当前订单
+	done    bool                // 是否已经没有更多数据
+	err     error               // 错误
+}
+
+// IterateOrders 遍历订单列表查询接口的所有分页
+// 按每页返回的 Total 判断结束，分页过程中 Total 变化时以最新的 Total 为准，返回空页或不足一页时也会结束
+func (c *Client) IterateOrders(ctx context.Context, req ApiOrderListRequest) *OrderIterator {
+	if req.Page == 0 {
+		req.Page = 1
+	}
+	if req.Limit == 0 {
+		req.Limit = ApiOrderListDefaultLimit
+	}
+	it := &OrderIterator{ctx: ctx, c: c, req: req}
+	it.err = req.Validate()
+	return it
+}
+
+// Next 获取下一条订单，没有更多订单或者发生错误时返回 false
+func (it *OrderIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		if !it.fetch() {
+			return false
+		}
+	}
+	it.current = it.page[it.index]
+	it.index++
+	return true
+}
+
+// 请求下一页
+func (it *OrderIterator) fetch() bool {
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+	result, err := it.c.ApiOrderList(it.ctx, it.req.Params())
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.page, it.index = result.Result.DataList, 0
+	it.fetched += len(it.page)
+	it.req.Page++
+	if len(it.page) == 0 || len(it.page) < it.req.Limit || it.fetched >= result.Result.Total {
+		it.done = true
+	}
+	return len(it.page) > 0
+}
+
+// Order 当前订单
+func (it *OrderIterator) Order() ApiOrderListOrder {
+	return it.current
+}
+
+// Err 遍历过程中发生的错误
+func (it *OrderIterator) Err() error {
+	return it.err
+}
+
+// Close 提前结束遍历，之后 Next 返回 false
+func (it *OrderIterator) Close() {
+	it.done = true
+	it.page, it.index = nil, 0
+}
+
+// CollectOrders 获取订单列表查询接口所有分页的订单
+func (c *Client) CollectOrders(ctx context.Context, req ApiOrderListRequest) ([]ApiOrderListOrder, error) {
+	var orders []ApiOrderListOrder
+	it := c.IterateOrders(ctx, req)
+	defer it.Close()
+	for it.Next() {
+		orders = append(orders, it.Order())
+	}
+	return orders, it.Err()
+}
@@ -0,0 +1,191 @@
+package meituan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 订单列表测试服务，按页码返回订单id和总数，订单的支付时间为查询的起始时间，总数为负数时返回服务端异常
+type orderListStub struct {
+	mu       sync.Mutex
+	pages    []int // 请求的页码
+	response func(r *http.Request, page int) ([]string, int)
+}
+
+func (s *orderListStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	s.mu.Lock()
+	s.pages = append(s.pages, page)
+	s.mu.Unlock()
+
+	ids, total := s.response(r, page)
+	if total < 0 {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	response := ApiOrderListResponse{DataList: []ApiOrderListOrder{}, Total: total}
+	for _, id := range ids {
+		response.DataList = append(response.DataList, ApiOrderListOrder{Orderid: id, Paytime: r.URL.Query().Get("startTime"), Status: int(OrderStatusPaid)})
+	}
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// 请求的页码
+func (s *orderListStub) requestedPages() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.pages...)
+}
+
+// 订单id
+func orderListIDs(orders []ApiOrderListOrder) []string {
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.Orderid)
+	}
+	return ids
+}
+
+// 每页2条的订单列表查询条件
+func testOrderListRequest() ApiOrderListRequest {
+	start := time.Unix(1700000000, 0)
+	return ApiOrderListRequest{StartTime: start, EndTime: start.Add(time.Hour), Limit: 2}
+}
+
+func TestIterateOrders(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages map[int][]string
+		total map[int]int
+		ids   []string
+		want  []int
+	}{
+		{
+			name:  "按总数结束",
+			pages: map[int][]string{1: {"1", "2"}, 2: {"3", "4"}},
+			total: map[int]int{1: 4, 2: 4},
+			ids:   []string{"1", "2", "3", "4"},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "分页过程中总数增加",
+			pages: map[int][]string{1: {"1", "2"}, 2: {"3", "4"}, 3: {"5"}},
+			total: map[int]int{1: 3, 2: 5, 3: 5},
+			ids:   []string{"1", "2", "3", "4", "5"},
+			want:  []int{1, 2, 3},
+		},
+		{
+			name:  "分页过程中总数减少",
+			pages: map[int][]string{1: {"1", "2"}, 2: {"3", "4"}, 3: {"5", "6"}},
+			total: map[int]int{1: 6, 2: 4},
+			ids:   []string{"1", "2", "3", "4"},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "总数未到时返回空页",
+			pages: map[int][]string{1: {"1", "2"}},
+			total: map[int]int{1: 6, 2: 6},
+			ids:   []string{"1", "2"},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "不足一页",
+			pages: map[int][]string{1: {"1", "2"}, 2: {"3"}},
+			total: map[int]int{1: 10, 2: 10},
+			ids:   []string{"1", "2", "3"},
+			want:  []int{1, 2},
+		},
+		{
+			name:  "没有订单",
+			total: map[int]int{1: 0},
+			ids:   []string{},
+			want:  []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := &orderListStub{response: func(r *http.Request, page int) ([]string, int) {
+				return tt.pages[page], tt.total[page]
+			}}
+			c := newTestClient(t, stub)
+			orders, err := c.CollectOrders(context.Background(), testOrderListRequest())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := orderListIDs(orders); !reflect.DeepEqual(ids, tt.ids) {
+				t.Errorf("订单 %v, want %v", ids, tt.ids)
+			}
+			if pages := stub.requestedPages(); !reflect.DeepEqual(pages, tt.want) {
+				t.Errorf("请求页码 %v, want %v", pages, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderIteratorClose(t *testing.T) {
+	stub := &orderListStub{response: func(r *http.Request, page int) ([]string, int) {
+		return []string{strconv.Itoa(page*2 - 1), strconv.Itoa(page * 2)}, 100
+	}}
+	c := newTestClient(t, stub)
+
+	it := c.IterateOrders(context.Background(), testOrderListRequest())
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Order().Orderid)
+		if len(ids) == 3 {
+			it.Close()
+		}
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2", "3"}) {
+		t.Errorf("订单 %v", ids)
+	}
+	// 提前结束后不再请求
+	if it.Next() {
+		t.Error("Close 后 Next 返回 true")
+	}
+	if pages := stub.requestedPages(); !reflect.DeepEqual(pages, []int{1, 2}) {
+		t.Errorf("请求页码 %v", pages)
+	}
+}
+
+func TestOrderIteratorErrors(t *testing.T) {
+	stub := &orderListStub{response: func(r *http.Request, page int) ([]string, int) {
+		return []string{"1", "2"}, 100
+	}}
+	c := newTestClient(t, stub)
+
+	// 查询条件错误时不请求
+	req := testOrderListRequest()
+	req.EndTime = req.StartTime
+	it := c.IterateOrders(context.Background(), req)
+	if it.Next() || it.Err() == nil {
+		t.Errorf("查询条件错误 Err = %v", it.Err())
+	}
+
+	// 分页过程中取消
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it = c.IterateOrders(ctx, testOrderListRequest())
+	n := 0
+	for it.Next() {
+		if n++; n == 2 {
+			cancel()
+		}
+	}
+	if !errors.Is(it.Err(), context.Canceled) || n != 2 {
+		t.Errorf("取消后 Err = %v, 订单 %d 条", it.Err(), n)
+	}
+	if pages := stub.requestedPages(); !reflect.DeepEqual(pages, []int{1}) {
+		t.Errorf("请求页码 %v", pages)
+	}
+}
@@ -0,0 +1,121 @@
+package meituan
+
+import (
+	"context"
+	"fmt"
+	"go.dtapp.net/gorequest"
+	"time"
+)
+
+// 订单列表查询限制
+const (
+	ApiOrderListMaxWindow    = 24 * time.Hour // 单次查询的最大时间范围
+	ApiOrderListMaxLimit     = 100            // 每页最大条数
+	ApiOrderListDefaultLimit = 100            // 每页默认条数
+)
+
+// OrderType 订单类型
+type OrderType int
+
+const (
+	OrderTypeGroup   OrderType = 0 // 团购订单
+	OrderTypeHotel   OrderType = 2 // 酒店订单
+	OrderTypeWaimai  OrderType = 4 // 外卖订单
+	OrderTypePhone   OrderType = 5 // 话费订单
+	OrderTypeShangou OrderType = 6 // 闪购订单
+)
+
+// OrderQueryTimeType 查询时间类型
+type OrderQueryTimeType int
+
+const (
+	OrderQueryTimeTypePay OrderQueryTimeType = 1 // 按订单支付时间查询
+	OrderQueryTimeTypeMod OrderQueryTimeType = 2 // 按订单更新时间查询
+)
+
+// BusinessLine 业务线
+type BusinessLine int
+
+const (
+	BusinessLineDaocan  BusinessLine = 1 // 到餐
+	BusinessLineDaozong BusinessLine = 2 // 到综
+	BusinessLineHotel   BusinessLine = 3 // 酒店
+	BusinessLineWaimai  BusinessLine = 4 // 外卖
+	BusinessLinePhone   BusinessLine = 5 // 话费
+	BusinessLineShangou BusinessLine = 6 // 闪购
+	BusinessLineYouxuan BusinessLine = 7 // 优选
+)
+
+// ApiOrderListRequest 订单列表查询请求参数
+type ApiOrderListRequest struct {
+	Type          *OrderType         // 订单类型，为空时不传
+	StartTime     time.Time          // 查询起始时间
+	EndTime       time.Time          // 查询截止时间，与起始时间最多相差 ApiOrderListMaxWindow
+	Page          int                // 页码，从1开始，默认1
+	Limit         int                // 每页条数，默认100，最大100
+	QueryTimeType OrderQueryTimeType // 查询时间类型，默认按订单支付时间查询
+	BusinessLine  BusinessLine       // 业务线，为0时不传
+}
+
+// Validate 校验请求参数
+func (r ApiOrderListRequest) Validate() error {
+	if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return fmt.Errorf("%w: 查询起始时间和截止时间不能为空", ErrInvalidRequest)
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return fmt.Errorf("%w: 查询截止时间必须晚于起始时间", ErrInvalidRequest)
+	}
+	if r.EndTime.Sub(r.StartTime) > ApiOrderListMaxWindow {
+		return fmt.Errorf("%w: 查询时间范围不能超过 %s", ErrInvalidRequest, ApiOrderListMaxWindow)
+	}
+	if r.Page < 0 {
+		return fmt.Errorf("%w: 页码不能小于1", ErrInvalidRequest)
+	}
+	if r.Limit < 0 || r.Limit > ApiOrderListMaxLimit {
+		return fmt.Errorf("%w: 每页条数必须在1到%d之间", ErrInvalidRequest, ApiOrderListMaxLimit)
+	}
+	switch r.QueryTimeType {
+	case 0, OrderQueryTimeTypePay, OrderQueryTimeTypeMod:
+	default:
+		return fmt.Errorf("%w: 查询时间类型 %d 不支持", ErrInvalidRequest, r.QueryTimeType)
+	}
+	return nil
+}
+
+// Params 转换为请求参数
+func (r ApiOrderListRequest) Params() gorequest.Params {
+	params := gorequest.NewParams()
+	if r.Type != nil {
+		params.Set("type", int(*r.Type))
+	}
+	params.Set("startTime", r.StartTime.Unix())
+	params.Set("endTime", r.EndTime.Unix())
+	page := r.Page
+	if page == 0 {
+		page = 1
+	}
+	params.Set("page", page)
+	limit := r.Limit
+	if limit == 0 {
+		limit = ApiOrderListDefaultLimit
+	}
+	params.Set("limit", limit)
+	queryTimeType := r.QueryTimeType
+	if queryTimeType == 0 {
+		queryTimeType = OrderQueryTimeTypePay
+	}
+	params.Set("queryTimeType", int(queryTimeType))
+	if r.BusinessLine != 0 {
+		params.Set("businessLine", int(r.BusinessLine))
+	}
+	return params
+}
+
+// ApiOrderListWithRequest 订单列表查询接口（新版），使用类型化的请求参数并在请求前校验
+// https://union.meituan.com/v2/apiDetail?id=23
+func (c *Client) ApiOrderListWithRequest(ctx context.Context, req ApiOrderListRequest) (*ApiOrderListResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return c.ApiOrderList(ctx, req.Params())
+}
@@ -0,0 +1,108 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestApiOrderListRequestValidate(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	valid := ApiOrderListRequest{StartTime: start, EndTime: start.Add(time.Hour)}
+	tests := []struct {
+		name   string
+		modify func(r *ApiOrderListRequest)
+		valid  bool
+	}{
+		{name: "默认值", modify: func(r *ApiOrderListRequest) {}, valid: true},
+		{name: "缺少起始时间", modify: func(r *ApiOrderListRequest) { r.StartTime = time.Time{} }},
+		{name: "缺少截止时间", modify: func(r *ApiOrderListRequest) { r.EndTime = time.Time{} }},
+		{name: "截止时间等于起始时间", modify: func(r *ApiOrderListRequest) { r.EndTime = r.StartTime }},
+		{name: "截止时间早于起始时间", modify: func(r *ApiOrderListRequest) { r.EndTime = r.StartTime.Add(-time.Second) }},
+		{name: "最大时间范围", modify: func(r *ApiOrderListRequest) { r.EndTime = r.StartTime.Add(ApiOrderListMaxWindow) }, valid: true},
+		{name: "超过最大时间范围", modify: func(r *ApiOrderListRequest) { r.EndTime = r.StartTime.Add(ApiOrderListMaxWindow + time.Second) }},
+		{name: "页码为负数", modify: func(r *ApiOrderListRequest) { r.Page = -1 }},
+		{name: "每页1条", modify: func(r *ApiOrderListRequest) { r.Limit = 1 }, valid: true},
+		{name: "每页最大条数", modify: func(r *ApiOrderListRequest) { r.Limit = ApiOrderListMaxLimit }, valid: true},
+		{name: "超过每页最大条数", modify: func(r *ApiOrderListRequest) { r.Limit = ApiOrderListMaxLimit + 1 }},
+		{name: "每页条数为负数", modify: func(r *ApiOrderListRequest) { r.Limit = -1 }},
+		{name: "按更新时间查询", modify: func(r *ApiOrderListRequest) { r.QueryTimeType = OrderQueryTimeTypeMod }, valid: true},
+		{name: "不支持的查询时间类型", modify: func(r *ApiOrderListRequest) { r.QueryTimeType = 3 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.modify(&r)
+			err := r.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Validate = %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidRequest) {
+				t.Errorf("Validate = %v, want ErrInvalidRequest", err)
+			}
+		})
+	}
+}
+
+func TestApiOrderListRequestParams(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	// 默认页码、条数和查询时间类型
+	params := ApiOrderListRequest{StartTime: start, EndTime: start.Add(time.Hour)}.Params()
+	want := map[string]any{"startTime": int64(1700000000), "endTime": int64(1700003600), "page": 1, "limit": ApiOrderListDefaultLimit, "queryTimeType": int(OrderQueryTimeTypePay)}
+	if !reflect.DeepEqual(map[string]any(params), want) {
+		t.Errorf("Params = %v, want %v", params, want)
+	}
+
+	orderType := OrderTypeWaimai
+	params = ApiOrderListRequest{
+		Type:          &orderType,
+		StartTime:     start,
+		EndTime:       start.Add(time.Hour),
+		Page:          3,
+		Limit:         20,
+		QueryTimeType: OrderQueryTimeTypeMod,
+		BusinessLine:  BusinessLineWaimai,
+	}.Params()
+	want = map[string]any{"type": int(OrderTypeWaimai), "startTime": int64(1700000000), "endTime": int64(1700003600), "page": 3, "limit": 20, "queryTimeType": int(OrderQueryTimeTypeMod), "businessLine": int(BusinessLineWaimai)}
+	if !reflect.DeepEqual(map[string]any(params), want) {
+		t.Errorf("Params = %v, want %v", params, want)
+	}
+
+	// 团购订单类型为0，也需要传
+	orderType = OrderTypeGroup
+	if params = (ApiOrderListRequest{Type: &orderType, StartTime: start, EndTime: start.Add(time.Hour)}).Params(); params["type"] != 0 {
+		t.Errorf("type = %v", params["type"])
+	}
+}
+
+func TestApiOrderListWithRequest(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Query().Get("page") != "1" || r.URL.Query().Get("limit") != "100" {
+			t.Errorf("请求参数 %s", r.URL.RawQuery)
+		}
+		_, _ = w.Write([]byte(`{"dataList":[],"total":0}`))
+	}))
+	start := time.Unix(1700000000, 0)
+
+	// 校验失败时不发送请求
+	if _, err := c.ApiOrderListWithRequest(context.Background(), ApiOrderListRequest{StartTime: start, EndTime: start.Add(48 * time.Hour)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("err = %v", err)
+	}
+	if requests.Load() != 0 {
+		t.Error("校验失败时发送了请求")
+	}
+
+	if _, err := c.ApiOrderListWithRequest(context.Background(), ApiOrderListRequest{StartTime: start, EndTime: start.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("请求次数 %d", requests.Load())
+	}
+}
@@ -0,0 +1,167 @@
+package meituan
+
+import (
+	"go.dtapp.net/gojson"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 读取 testdata 中的接口返回并解析
+func decodeFixture(t *testing.T, name string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = gojson.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s 解析失败: %v", name, err)
+	}
+}
+
+func TestApiOrderResponseFixtures(t *testing.T) {
+	tests := []struct {
+		fixture       string
+		refunds       int
+		refundProfits int
+		consumes      int
+		want          Order
+	}{
+		{
+			fixture:       "api_order_single_refund.json",
+			refunds:       1,
+			refundProfits: 1,
+			want: Order{
+				OrderID:      "1001",
+				Status:       OrderStatusRefunded,
+				Quantity:     1,
+				PayPrice:     2580,
+				Profit:       129,
+				RefundPrice:  2580,
+				RefundProfit: 129,
+				RefundTime:   time.Unix(1700003000, 0),
+				ModTime:      time.Unix(1700003600, 0),
+			},
+		},
+		{
+			fixture:       "api_order_multi_refund.json",
+			refunds:       2,
+			refundProfits: 2,
+			want: Order{
+				OrderID:      "1002",
+				Status:       OrderStatusCompleted,
+				Quantity:     3,
+				PayPrice:     9000,
+				Profit:       450,
+				RefundPrice:  6000,
+				RefundProfit: 300,
+				RefundTime:   time.Unix(1700080000, 0),
+				ModTime:      time.Unix(1700090000, 0),
+				CouponCode:   "C1002",
+			},
+		},
+		{
+			fixture:  "api_order_partial_consume.json",
+			consumes: 1,
+			want: Order{
+				OrderID:    "1003",
+				Status:     OrderStatusPaid,
+				Quantity:   2,
+				PayPrice:   6000,
+				Profit:     300,
+				ModTime:    time.Unix(1700070000, 0),
+				CouponCode: "C1003",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			var response ApiOrderResponse
+			decodeFixture(t, tt.fixture, &response)
+			d := response.Data
+			if len(d.RefundInfoList) != tt.refunds || len(d.RefundProfitList) != tt.refundProfits || len(d.ConsumeProfitList) != tt.consumes {
+				t.Errorf("列表数量 %d/%d/%d, want %d/%d/%d", len(d.RefundInfoList), len(d.RefundProfitList), len(d.ConsumeProfitList), tt.refunds, tt.refundProfits, tt.consumes)
+			}
+
+			order, err := response.Order()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if order.Source != OrderSourceQuery {
+				t.Errorf("Source = %v", order.Source)
+			}
+			w := tt.want
+			if order.OrderID != w.OrderID || order.Status != w.Status || order.Quantity != w.Quantity || order.CouponCode != w.CouponCode {
+				t.Errorf("订单 %+v", order)
+			}
+			if order.PayPrice != w.PayPrice || order.Profit != w.Profit || order.RefundPrice != w.RefundPrice || order.RefundProfit != w.RefundProfit {
+				t.Errorf("金额 payprice=%s profit=%s refundprice=%s refundprofit=%s", order.PayPrice, order.Profit, order.RefundPrice, order.RefundProfit)
+			}
+			if !order.RefundTime.Equal(w.RefundTime) || !order.ModTime.Equal(w.ModTime) {
+				t.Errorf("时间 refundtime=%v modtime=%v", order.RefundTime, order.ModTime)
+			}
+		})
+	}
+}
+
+func TestApiOrderListFixture(t *testing.T) {
+	var response ApiOrderListResponse
+	decodeFixture(t, "api_order_list.json", &response)
+	if response.Total != 2 || len(response.DataList) != 2 {
+		t.Fatalf("total = %d, len = %d", response.Total, len(response.DataList))
+	}
+
+	o := response.DataList[0]
+	extra, err := o.ExtraInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extra.Quantity != 3 || extra.CouponCode != "C1002" || len(extra.RefundInfoList) != 2 || extra.RefundInfoList[1].RefundPrice != "30.00" {
+		t.Errorf("ExtraInfo = %+v", extra)
+	}
+	extraMap, err := o.ExtraMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extraMap["other"] != "x" {
+		t.Errorf("ExtraMap = %v", extraMap)
+	}
+
+	businessTypes, err := o.TradeTypeBusinessTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[TradeType][]int{TradeTypeCps: {1, 2}, TradeTypeFirstBuy: {4}}; !reflect.DeepEqual(businessTypes, want) {
+		t.Errorf("TradeTypeBusinessTypes = %v, want %v", businessTypes, want)
+	}
+
+	order, err := o.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.RefundPrice != 6000 || order.RefundProfit != 300 || !order.RefundTime.Equal(time.Unix(1700080000, 0)) || len(order.TradeTypes) != 2 || order.Quantity != 3 || order.CouponCode != "C1002" {
+		t.Errorf("订单 %+v", order)
+	}
+
+	// 没有扩展信息的订单
+	empty := response.DataList[1]
+	if extra, err = empty.ExtraInfo(); err != nil || !reflect.DeepEqual(extra, ApiOrderListExtra{}) {
+		t.Errorf("ExtraInfo = %+v, %v", extra, err)
+	}
+	if extraMap, err = empty.ExtraMap(); err != nil || extraMap != nil {
+		t.Errorf("ExtraMap = %v, %v", extraMap, err)
+	}
+	if businessTypes, err = empty.TradeTypeBusinessTypes(); err != nil || businessTypes != nil {
+		t.Errorf("TradeTypeBusinessTypes = %v, %v", businessTypes, err)
+	}
+	if order, err = empty.Order(); err != nil || !order.Risk {
+		t.Errorf("风控订单 %+v, %v", order, err)
+	}
+
+	// 扩展信息格式错误
+	if _, err = (ApiOrderListOrder{Extra: "{"}).ExtraInfo(); err == nil {
+		t.Error("extra 格式错误时应返回错误")
+	}
+}
@@ -2,38 +2,106 @@ package meituan
 
 import (
 	"go.dtapp.net/gorequest"
+	"strings"
 )
 
 func (c *Client) GetSecret() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.config.secret
 }
 
 func (c *Client) SetSecret(v string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.config.secret = v
 	return c
 }
 
 func (c *Client) GetAppKey() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.config.appKey
 }
 
 func (c *Client) SetAppKey(v string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.config.appKey = v
 	return c
 }
 
+// SetCallbackAppKeys 设置订单回推允许的appkey，为空时只允许 AppKey
+func (c *Client) SetCallbackAppKeys(appKeys ...string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.config.callbackAppKeys = append([]string(nil), appKeys...)
+	return c
+}
+
+// 订单回推的appkey是否允许
+func (c *Client) isCallbackAppKey(appKey string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.config.callbackAppKeys) == 0 {
+		return appKey == c.config.appKey
+	}
+	for _, v := range c.config.callbackAppKeys {
+		if appKey == v {
+			return true
+		}
+	}
+	return false
+}
+
 // SetClientIP 配置
 func (c *Client) SetClientIP(clientIP string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.clientIP = clientIP
-	if c.httpClient != nil {
-		c.httpClient.SetClientIP(clientIP)
-	}
 	return c
 }
 
 // SetLogFun 设置日志记录函数
 func (c *Client) SetLogFun(logFun gorequest.LogFunc) {
-	if c.httpClient != nil {
-		c.httpClient.SetLogFunc(logFun)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.logFunc = logFun
+}
+
+// GetBaseURL 获取接口地址，联盟接口(api/*)、开放平台接口(poi/*)和CPS开放接口(cps_open/*)分别配置
+func (c *Client) GetBaseURL(path string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if strings.HasPrefix(path, "poi/") {
+		return c.config.poiBaseURL
+	}
+	if isCpsOpen(path) {
+		return c.config.cpsBaseURL
 	}
+	return c.config.unionBaseURL
+}
+
+// 是否CPS开放接口，使用请求头签名
+func isCpsOpen(path string) bool {
+	return strings.HasPrefix(path, "cps_open/")
+}
+
+// 获取接口请求地址，优先使用配置的接口路径覆盖
+func (c *Client) getUrl(path string) string {
+	c.mu.RLock()
+	override, ok := c.config.endpoints[path]
+	c.mu.RUnlock()
+	if ok && override != "" {
+		if strings.HasPrefix(override, "http://") || strings.HasPrefix(override, "https://") {
+			return override
+		}
+		return joinUrl(c.GetBaseURL(path), override)
+	}
+	return joinUrl(c.GetBaseURL(path), path)
+}
+
+// 拼接接口地址和路径
+func joinUrl(baseURL, path string) string {
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(path, "/")
 }
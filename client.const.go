@@ -1,7 +1,8 @@
 package meituan
 
 const (
-	apiUrl = "https://openapi.meituan.com/"
+	apiUrl     = "https://openapi.meituan.com/"
+	cpsOpenUrl = "https://media.meituan.com/"
 )
 
 const (
@@ -0,0 +1,82 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gorequest"
+	"go.dtapp.net/gotime"
+	"net/http"
+)
+
+// 签名方式
+type signMode int
+
+const (
+	signNone   signMode = iota // 不签名
+	signMD5                    // 请求参数携带 appkey 和 sign，见 MD5Signer
+	signHeader                 // CPS开放接口请求头签名，见 CpsStringToSign
+)
+
+// 返回内容格式
+type envelopeStyle int
+
+const (
+	envelopeStatus envelopeStyle = iota // status/des，status 非0为异常
+	envelopeCode                        // code/msg(message)，code 非0为异常
+)
+
+// 接口描述
+type endpoint struct {
+	path      string        // 接口路径
+	method    string        // 请求方式
+	sign      signMode      // 签名方式
+	timestamp bool          // 是否需要请求时刻10位时间戳 ts，有效期60s
+	envelope  envelopeStyle // 返回内容格式
+}
+
+// 接口列表
+var (
+	endpointApiOrderList            = endpoint{path: "api/orderList", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeStatus}
+	endpointApiOrder                = endpoint{path: "api/order", method: http.MethodGet, sign: signMD5, envelope: envelopeStatus}
+	endpointApiGenerateLink         = endpoint{path: "api/generateLink", method: http.MethodGet, sign: signMD5, envelope: envelopeStatus}
+	endpointApiMiniCode             = endpoint{path: "api/miniCode", method: http.MethodGet, sign: signMD5, envelope: envelopeStatus}
+	endpointApiGetQuaLitYsCoreBySid = endpoint{path: "api/getqualityscorebysid", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeStatus}
+	endpointApiMtUnionCity          = endpoint{path: "api/mtunion/city", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeCode}
+	endpointApiMtUnionCategory      = endpoint{path: "api/mtunion/category", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeCode}
+	endpointApiMtUnionSku           = endpoint{path: "api/mtunion/sku", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeCode}
+	endpointApiMtUnionPoi           = endpoint{path: "api/mtunion/poi", method: http.MethodGet, sign: signMD5, timestamp: true, envelope: envelopeCode}
+	endpointPoiCity                 = endpoint{path: "poi/city", method: http.MethodGet, envelope: envelopeCode}
+	endpointPoiDistrict             = endpoint{path: "poi/district", method: http.MethodGet, envelope: envelopeCode}
+	endpointPoiArea                 = endpoint{path: "poi/area", method: http.MethodGet, envelope: envelopeCode}
+	endpointPoiCategory             = endpoint{path: "poi/category", method: http.MethodGet, envelope: envelopeCode}
+	endpointCpsOpenGetReferralLink  = endpoint{path: "cps_open/common/api/v1/get_referral_link", method: http.MethodPost, sign: signHeader, envelope: envelopeCode}
+)
+
+// 请求时间戳(ts)，10位时间戳，每次请求重新获取
+var requestTimestamp = func() int64 {
+	return gotime.Current().Timestamp()
+}
+
+// 每次请求前重新计算时间戳和签名，ts 有效期为60s，重试时不能沿用首次请求的参数
+func (c *Client) prepareParams(ep endpoint, param gorequest.Params) gorequest.Params {
+	params := gorequest.NewParamsWith(param)
+	if ep.timestamp {
+		params.Set("ts", requestTimestamp())
+	}
+	if ep.sign == signMD5 {
+		params.Set("appkey", c.GetAppKey()) // 媒体名称，可在推广者备案-媒体管理中查询
+		params.Set("sign", c.SignParams(params))
+	}
+	return params
+}
+
+// 调用接口并解析返回内容
+func call[Resp any](ctx context.Context, c *Client, ep endpoint, params gorequest.Params) (response Resp, request gorequest.Response, err error) {
+
+	// OpenTelemetry链路追踪
+	ctx = c.TraceStartSpan(ctx, ep.path)
+	defer c.TraceEndSpan(ctx)
+
+	// 请求
+	request, err = c.request(ctx, ep, params, &response)
+	return response, request, err
+}
@@ -0,0 +1,93 @@
+package meituan
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestEndpointPaths(t *testing.T) {
+	want := map[string]struct {
+		path      string
+		method    string
+		sign      signMode
+		timestamp bool
+	}{
+		"ApiOrderList":            {"/api/orderList", http.MethodGet, signMD5, true},
+		"ApiOrder":                {"/api/order", http.MethodGet, signMD5, false},
+		"ApiGenerateLink":         {"/api/generateLink", http.MethodGet, signMD5, false},
+		"ApiMiniCode":             {"/api/miniCode", http.MethodGet, signMD5, false},
+		"ApiGetQuaLitYsCoreBySid": {"/api/getqualityscorebysid", http.MethodGet, signMD5, true},
+		"ApiMtUnionCity":          {"/api/mtunion/city", http.MethodGet, signMD5, true},
+		"ApiMtUnionCategory":      {"/api/mtunion/category", http.MethodGet, signMD5, true},
+		"ApiMtUnionSku":           {"/api/mtunion/sku", http.MethodGet, signMD5, true},
+		"ApiMtUnionPoi":           {"/api/mtunion/poi", http.MethodGet, signMD5, true},
+		"PoiCity":                 {"/poi/city", http.MethodGet, signNone, false},
+		"PoiDistrict":             {"/poi/district", http.MethodGet, signNone, false},
+		"PoiArea":                 {"/poi/area", http.MethodGet, signNone, false},
+		"PoiCategory":             {"/poi/category", http.MethodGet, signNone, false},
+		"CpsOpenGetReferralLink":  {"/cps_open/common/api/v1/get_referral_link", http.MethodPost, signHeader, false},
+	}
+	if len(want) != len(testAPICalls) {
+		t.Fatalf("接口数量 %d 与期望 %d 不一致", len(testAPICalls), len(want))
+	}
+
+	var (
+		mu      sync.Mutex
+		request *http.Request
+	)
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		request = r
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"status":0,"code":0}`))
+	}))
+
+	for _, api := range testAPICalls {
+		t.Run(api.name, func(t *testing.T) {
+			expected, ok := want[api.name]
+			if !ok {
+				t.Fatalf("没有 %s 的期望路径", api.name)
+			}
+			if err := api.call(context.Background(), c); err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			r := request
+			mu.Unlock()
+
+			if r.URL.Path != expected.path {
+				t.Errorf("path = %s, want %s", r.URL.Path, expected.path)
+			}
+			if r.Method != expected.method {
+				t.Errorf("method = %s, want %s", r.Method, expected.method)
+			}
+			query := r.URL.Query()
+			if got := query.Has("ts"); got != expected.timestamp {
+				t.Errorf("ts = %v, want %v", got, expected.timestamp)
+			}
+			switch expected.sign {
+			case signMD5:
+				if query.Get("sign") == "" || query.Get("appkey") != "appkey" {
+					t.Errorf("缺少 appkey 或 sign: %s", r.URL.RawQuery)
+				}
+				params := make(map[string]any)
+				for k := range query {
+					params[k] = query.Get(k)
+				}
+				if !c.VerifySign(params, query.Get("sign")) {
+					t.Errorf("sign 校验失败: %s", r.URL.RawQuery)
+				}
+			case signHeader:
+				if r.Header.Get(HeaderCaSignature) == "" {
+					t.Errorf("缺少 %s 请求头", HeaderCaSignature)
+				}
+			default:
+				if query.Has("sign") || r.Header.Get(HeaderCaSignature) != "" {
+					t.Errorf("不需要签名的接口携带了签名")
+				}
+			}
+		})
+	}
+}
@@ -1,37 +1,105 @@
 package meituan
 
 import (
+	"fmt"
 	"go.dtapp.net/gorequest"
-	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"net/url"
+	"sync"
 )
 
 // ClientConfig 实例配置
 type ClientConfig struct {
-	Secret string // 秘钥
-	AppKey string // 渠道标记
+	Secret          string            // 秘钥
+	AppKey          string            // 渠道标记
+	BaseURL         string            // 接口地址，默认 https://openapi.meituan.com/
+	UnionBaseURL    string            // 联盟接口(api/*)地址，为空时使用 BaseURL
+	PoiBaseURL      string            // 开放平台接口(poi/*)地址，为空时使用 BaseURL
+	CpsBaseURL      string            // CPS开放接口(cps_open/*)地址，默认 https://media.meituan.com/
+	Endpoints       map[string]string // 接口路径覆盖，键为默认路径(如 api/orderList)，值为路径或完整地址
+	HTTPClient      *http.Client      // 自定义HTTP请求客户端，可配置连接池、代理、证书等
+	Transport       http.RoundTripper // 自定义传输层，优先于 HTTPClient.Transport
+	Retry           *RetryConfig      // 重试配置，为空时不重试
+	RateLimit       *RateLimitConfig  // 限流配置，为空时不限流
+	Signer          Signer            // 签名方式，默认 MD5Signer
+	CallbackAppKeys []string          // 订单回推允许的appkey，为空时只允许 AppKey
+	ErrorCodes      ErrorCodes        // 业务错误码分类，用于 IsRateLimited、IsSignError、IsTimestampExpired 和 IsRetryable
 }
 
 // Client 实例
+// 每次请求都会独立创建请求状态，可以在多个 goroutine 中并发使用
 type Client struct {
+	mu     sync.RWMutex // 保护以下可修改的配置
 	config struct {
-		secret string // 秘钥
-		appKey string // 渠道标记
+		secret          string            // 秘钥
+		appKey          string            // 渠道标记
+		unionBaseURL    string            // 联盟接口地址
+		poiBaseURL      string            // 开放平台接口地址
+		cpsBaseURL      string            // CPS开放接口地址
+		endpoints       map[string]string // 接口路径覆盖
+		callbackAppKeys []string          // 订单回推允许的appkey
 	}
-	httpClient *gorequest.App // HTTP请求客户端
-	clientIP   string         // 客户端IP
-	trace      bool           // OpenTelemetry链路追踪
-	span       trace.Span     // OpenTelemetry链路追踪
+	httpClient      *http.Client      // HTTP请求客户端
+	retry           RetryConfig       // 重试配置
+	rateLimiter     *rateLimiter      // 限流器
+	signer          Signer            // 签名方式
+	errorClassifier errorClassifier   // 业务错误码分类
+	clientIP        string            // 客户端IP
+	logFunc         gorequest.LogFunc // 日志记录函数
+	trace           bool              // OpenTelemetry链路追踪
 }
 
 // NewClient 创建实例化
 func NewClient(config *ClientConfig) (*Client, error) {
 	c := &Client{}
 
-	c.httpClient = gorequest.NewHttp()
-
 	c.config.secret = config.Secret
 	c.config.appKey = config.AppKey
 
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = apiUrl
+	}
+	c.config.unionBaseURL = baseURL
+	if config.UnionBaseURL != "" {
+		c.config.unionBaseURL = config.UnionBaseURL
+	}
+	c.config.poiBaseURL = baseURL
+	if config.PoiBaseURL != "" {
+		c.config.poiBaseURL = config.PoiBaseURL
+	}
+	c.config.cpsBaseURL = cpsOpenUrl
+	if config.CpsBaseURL != "" {
+		c.config.cpsBaseURL = config.CpsBaseURL
+	}
+	for _, v := range []string{c.config.unionBaseURL, c.config.poiBaseURL, c.config.cpsBaseURL} {
+		if u, err := url.Parse(v); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("接口地址格式错误: %q", v)
+		}
+	}
+	c.config.endpoints = make(map[string]string, len(config.Endpoints))
+	for path, override := range config.Endpoints {
+		c.config.endpoints[path] = override
+	}
+
+	c.config.callbackAppKeys = append([]string(nil), config.CallbackAppKeys...)
+
+	c.httpClient = newHttpClient(config.HTTPClient, config.Transport)
+
+	if config.Retry != nil {
+		c.retry = *config.Retry
+	}
+	c.retry = c.retry.withDefaults()
+
+	c.rateLimiter = newRateLimiter(config.RateLimit)
+
+	c.signer = config.Signer
+	if c.signer == nil {
+		c.signer = MD5Signer{}
+	}
+
+	c.errorClassifier = newErrorClassifier(config.ErrorCodes)
+
 	c.trace = true
 	return c, nil
 }
@@ -0,0 +1,170 @@
+package meituan
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"go.dtapp.net/gojson"
+	"go.dtapp.net/gorequest"
+	"go.dtapp.net/gotime"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"runtime"
+	"time"
+)
+
+// 创建HTTP请求客户端，在自定义的传输层外包装OpenTelemetry链路追踪
+func newHttpClient(httpClient *http.Client, transport http.RoundTripper) *http.Client {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+	if transport == nil {
+		transport = client.Transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	// https://uptrace.dev/get/instrument/opentelemetry-net-http.html
+	client.Transport = otelhttp.NewTransport(
+		transport,
+		otelhttp.WithClientTrace(func(ctx context.Context) *httptrace.ClientTrace {
+			return otelhttptrace.NewClientTrace(ctx)
+		}),
+	)
+	return client
+}
+
+// 发起HTTP请求，每次请求独立创建请求状态
+// signCps 为 true 时使用CPS开放接口的请求头签名
+func (c *Client) doHttp(ctx context.Context, method string, uri string, param gorequest.Params, signCps bool) (httpResponse gorequest.Response, err error) {
+
+	// 开始时间
+	start := time.Now().UTC()
+
+	// 赋值
+	httpResponse.RequestTime = gotime.Current().Time
+	httpResponse.RequestUri = uri
+	httpResponse.RequestMethod = method
+	httpResponse.RequestParams = gorequest.NewParamsWith(param)
+	httpResponse.RequestHeader = gorequest.NewHeaders()
+	httpResponse.RequestHeader.Set("Content-Type", "application/json")
+
+	// 跟踪编号
+	httpResponse.RequestID = gorequest.GetRequestIDContext(ctx)
+	if httpResponse.RequestID != "" {
+		httpResponse.RequestHeader.Set("X-Request-ID", httpResponse.RequestID)
+	}
+
+	// 请求内容
+	var body []byte
+	var reqBody io.Reader
+	if method != http.MethodGet {
+		body, err = gojson.Marshal(httpResponse.RequestParams)
+		if err != nil {
+			return httpResponse, err
+		}
+		reqBody = bytes.NewReader(body)
+	}
+
+	// 创建请求
+	req, err := http.NewRequestWithContext(ctx, method, uri, reqBody)
+	if err != nil {
+		return httpResponse, err
+	}
+
+	// GET 请求携带查询参数
+	if method == http.MethodGet {
+		q := req.URL.Query()
+		for k, v := range httpResponse.RequestParams {
+			q.Add(k, SignValue(v))
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+
+	// 设置请求头
+	for key, value := range httpResponse.RequestHeader {
+		req.Header.Set(key, value)
+	}
+
+	// CPS开放接口签名
+	if signCps {
+		signCpsRequest(req, body, c.GetAppKey(), c.GetSecret(), time.Now().UnixMilli())
+		for key := range req.Header {
+			httpResponse.RequestHeader.Set(key, req.Header.Get(key))
+		}
+	}
+
+	// 发送请求
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return httpResponse, err
+	}
+	defer resp.Body.Close() // 关闭连接
+
+	// 请求消耗时长
+	httpResponse.RequestCostTime = time.Now().UTC().Sub(start).Milliseconds()
+
+	var reader io.ReadCloser
+	switch resp.Header.Get("Content-Encoding") {
+	case "gzip":
+		reader, err = gzip.NewReader(resp.Body)
+		if err != nil {
+			return httpResponse, err
+		}
+	case "deflate":
+		reader = flate.NewReader(resp.Body)
+	default:
+		reader = resp.Body
+	}
+	defer reader.Close() // nolint
+
+	// 读取内容
+	respBody, err := io.ReadAll(reader)
+	if err != nil {
+		return httpResponse, err
+	}
+
+	// 赋值
+	httpResponse.ResponseTime = gotime.Current().Time
+	httpResponse.ResponseStatus = resp.Status
+	httpResponse.ResponseStatusCode = resp.StatusCode
+	httpResponse.ResponseHeader = resp.Header
+	httpResponse.ResponseBody = respBody
+	httpResponse.ResponseContentLength = resp.ContentLength
+
+	// 调用日志记录函数
+	c.mu.RLock()
+	logFunc, clientIP := c.logFunc, c.clientIP
+	c.mu.RUnlock()
+	if logFunc != nil {
+		logFunc(ctx, &gorequest.LogResponse{
+			SpanID:             c.TraceGetSpanID(ctx),
+			TraceID:            c.TraceGetTraceID(ctx),
+			RequestID:          httpResponse.RequestID,
+			RequestTime:        httpResponse.RequestTime,
+			RequestUri:         httpResponse.RequestUri,
+			RequestUrl:         req.URL.Scheme + "://" + req.URL.Host + req.URL.Path,
+			RequestApi:         req.URL.Path,
+			RequestMethod:      httpResponse.RequestMethod,
+			RequestParams:      gojson.JsonEncodeNoError(httpResponse.RequestParams),
+			RequestHeader:      gojson.JsonEncodeNoError(httpResponse.RequestHeader),
+			RequestCostTime:    httpResponse.RequestCostTime,
+			RequestIP:          clientIP,
+			ResponseHeader:     gojson.JsonEncodeNoError(httpResponse.ResponseHeader),
+			ResponseStatusCode: httpResponse.ResponseStatusCode,
+			ResponseBody:       string(httpResponse.ResponseBody),
+			ResponseBodyJson:   gojson.JsonEncodeNoError(gojson.JsonDecodeNoError(string(httpResponse.ResponseBody))),
+			ResponseBodyXml:    gojson.XmlEncodeNoError(gojson.XmlDecodeNoError(httpResponse.ResponseBody)),
+			ResponseTime:       httpResponse.ResponseTime,
+			GoVersion:          runtime.Version(),
+			SdkVersion:         Version,
+		})
+	}
+
+	return httpResponse, nil
+}
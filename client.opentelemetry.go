@@ -8,61 +8,92 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// 上下文中保存本SDK创建的Span，避免误操作调用方的Span
+type spanContextKey struct{}
+
 // SetTrace 设置OpenTelemetry链路追踪
 func (c *Client) SetTrace(trace bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.trace = trace
-	c.httpClient.SetTrace(trace)
+}
+
+// 是否开启OpenTelemetry链路追踪
+func (c *Client) traceEnabled() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.trace
+}
+
+// 获取上下文中本SDK创建的Span
+func spanFromContext(ctx context.Context) trace.Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(trace.Span)
+	return span
 }
 
 // TraceStartSpan 开始OpenTelemetry链路追踪状态
+// 以 ctx 中的Span为父级创建本次请求的Span，并保存在返回的上下文中
 func (c *Client) TraceStartSpan(ctx context.Context, spanName string) context.Context {
-	if c.trace {
+	if c.traceEnabled() {
 		tr := otel.Tracer("go.dtapp.net/meituan", trace.WithInstrumentationVersion(Version))
-		ctx, c.span = tr.Start(ctx, "meituan."+spanName, trace.WithSpanKind(trace.SpanKindClient))
+		var span trace.Span
+		ctx, span = tr.Start(ctx, "meituan."+spanName, trace.WithSpanKind(trace.SpanKindClient))
+		span.SetAttributes(attribute.String("meituan.endpoint", spanName))
+		ctx = context.WithValue(ctx, spanContextKey{}, span)
 	}
 	return ctx
 }
 
 // TraceEndSpan 结束OpenTelemetry链路追踪状态
-func (c *Client) TraceEndSpan() {
-	if c.trace && c.span != nil {
-		c.span.End()
+func (c *Client) TraceEndSpan(ctx context.Context) {
+	if span := spanFromContext(ctx); span != nil {
+		span.End()
 	}
 }
 
 // TraceSetAttributes 设置OpenTelemetry链路追踪属性
-func (c *Client) TraceSetAttributes(kv ...attribute.KeyValue) {
-	if c.trace && c.span != nil {
-		c.span.SetAttributes(kv...)
+func (c *Client) TraceSetAttributes(ctx context.Context, kv ...attribute.KeyValue) {
+	if span := spanFromContext(ctx); span != nil {
+		span.SetAttributes(kv...)
+	}
+}
+
+// TraceAddEvent 添加OpenTelemetry链路追踪事件
+func (c *Client) TraceAddEvent(ctx context.Context, name string, kv ...attribute.KeyValue) {
+	if span := spanFromContext(ctx); span != nil {
+		span.AddEvent(name, trace.WithAttributes(kv...))
 	}
 }
 
 // TraceSetStatus 设置OpenTelemetry链路追踪状态
-func (c *Client) TraceSetStatus(code codes.Code, description string) {
-	if c.trace && c.span != nil {
-		c.span.SetStatus(code, description)
+func (c *Client) TraceSetStatus(ctx context.Context, code codes.Code, description string) {
+	if span := spanFromContext(ctx); span != nil {
+		span.SetStatus(code, description)
 	}
 }
 
 // TraceRecordError 记录OpenTelemetry链路追踪错误
-func (c *Client) TraceRecordError(err error, options ...trace.EventOption) {
-	if c.trace && c.span != nil {
-		c.span.RecordError(err, options...)
+func (c *Client) TraceRecordError(ctx context.Context, err error, options ...trace.EventOption) {
+	if span := spanFromContext(ctx); span != nil {
+		span.RecordError(err, options...)
 	}
 }
 
 // TraceGetTraceID 获取OpenTelemetry链路追踪TraceID
-func (c *Client) TraceGetTraceID() (traceID string) {
-	if c.trace && c.span != nil {
-		traceID = c.span.SpanContext().TraceID().String()
+func (c *Client) TraceGetTraceID(ctx context.Context) (traceID string) {
+	if span := spanFromContext(ctx); span != nil {
+		traceID = span.SpanContext().TraceID().String()
 	}
 	return traceID
 }
 
 // TraceGetSpanID 获取OpenTelemetry链路追踪SpanID
-func (c *Client) TraceGetSpanID() (spanID string) {
-	if c.trace && c.span != nil {
-		spanID = c.span.SpanContext().SpanID().String()
+func (c *Client) TraceGetSpanID(ctx context.Context) (spanID string) {
+	if span := spanFromContext(ctx); span != nil {
+		spanID = span.SpanContext().SpanID().String()
 	}
 	return spanID
 }
@@ -0,0 +1,170 @@
+package meituan
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit 令牌桶限流
+type RateLimit struct {
+	Rate  float64 // 每秒请求数，小于等于0时不限制
+	Burst int     // 突发请求数，默认为 Rate 向上取整
+}
+
+// RateLimitConfig 限流配置
+type RateLimitConfig struct {
+	RateLimit                      // 每个appkey的总限流
+	Endpoints map[string]RateLimit // 接口限流，键为默认路径(如 api/generateLink)
+}
+
+// RateLimitStat 限流等待统计
+type RateLimitStat struct {
+	Requests  int64         // 请求次数
+	Waits     int64         // 需要等待的请求次数
+	TotalWait time.Duration // 总等待时长
+	MaxWait   time.Duration // 最长等待时长
+}
+
+// 令牌桶
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64   // 每秒生成令牌数
+	burst  float64   // 令牌桶容量
+	tokens float64   // 当前令牌数，为负数时表示已被预约
+	last   time.Time // 上次计算令牌的时间
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limit.Rate))
+	}
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// 预约一个令牌，返回需要等待的时长
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// 取消预约，归还令牌
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+1)
+}
+
+// 限流器，按appkey总限流，按appkey和接口分别限流
+type rateLimiter struct {
+	config  RateLimitConfig
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket   // 令牌桶
+	stats   map[string]*RateLimitStat // 等待统计，键为接口路径
+}
+
+func newRateLimiter(config *RateLimitConfig) *rateLimiter {
+	if config == nil {
+		return nil
+	}
+	l := &rateLimiter{
+		config:  RateLimitConfig{RateLimit: config.RateLimit, Endpoints: make(map[string]RateLimit, len(config.Endpoints))},
+		buckets: make(map[string]*tokenBucket),
+		stats:   make(map[string]*RateLimitStat),
+	}
+	for path, limit := range config.Endpoints {
+		l.config.Endpoints[path] = limit
+	}
+	return l
+}
+
+// 获取本次请求需要使用的令牌桶
+func (l *rateLimiter) bucketsFor(appKey string, path string) []*tokenBucket {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var buckets []*tokenBucket
+	if l.config.Rate > 0 {
+		key := appKey
+		if _, ok := l.buckets[key]; !ok {
+			l.buckets[key] = newTokenBucket(l.config.RateLimit)
+		}
+		buckets = append(buckets, l.buckets[key])
+	}
+	if limit, ok := l.config.Endpoints[path]; ok && limit.Rate > 0 {
+		key := appKey + "|" + path
+		if _, ok := l.buckets[key]; !ok {
+			l.buckets[key] = newTokenBucket(limit)
+		}
+		buckets = append(buckets, l.buckets[key])
+	}
+	return buckets
+}
+
+// 等待获取令牌，上下文取消时归还已预约的令牌并返回错误
+func (l *rateLimiter) wait(ctx context.Context, appKey string, path string) (time.Duration, error) {
+	buckets := l.bucketsFor(appKey, path)
+
+	now := time.Now()
+	var wait time.Duration
+	for _, bucket := range buckets {
+		if d := bucket.reserve(now); d > wait {
+			wait = d
+		}
+	}
+
+	if wait > 0 {
+		if err := sleepContext(ctx, wait); err != nil {
+			for _, bucket := range buckets {
+				bucket.cancel()
+			}
+			return 0, err
+		}
+	}
+
+	l.record(path, wait)
+	return wait, nil
+}
+
+// 记录等待统计
+func (l *rateLimiter) record(path string, wait time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stat, ok := l.stats[path]
+	if !ok {
+		stat = &RateLimitStat{}
+		l.stats[path] = stat
+	}
+	stat.Requests++
+	if wait > 0 {
+		stat.Waits++
+		stat.TotalWait += wait
+		if wait > stat.MaxWait {
+			stat.MaxWait = wait
+		}
+	}
+}
+
+// RateLimitStats 获取限流等待统计，键为接口路径，未配置限流时返回 nil
+func (c *Client) RateLimitStats() map[string]RateLimitStat {
+	if c.rateLimiter == nil {
+		return nil
+	}
+	c.rateLimiter.mu.Lock()
+	defer c.rateLimiter.mu.Unlock()
+	stats := make(map[string]RateLimitStat, len(c.rateLimiter.stats))
+	for path, stat := range c.rateLimiter.stats {
+		stats[path] = *stat
+	}
+	return stats
+}
@@ -0,0 +1,153 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	now := bucket.last
+
+	// 突发请求不等待，之后按速率排队
+	want := []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond}
+	for i, w := range want {
+		if got := bucket.reserve(now); got != w {
+			t.Errorf("第%d次预约等待 %v, want %v", i+1, got, w)
+		}
+	}
+
+	// 令牌按速率恢复，不超过容量
+	bucket = newTokenBucket(RateLimit{Rate: 10, Burst: 2})
+	now = bucket.last
+	bucket.reserve(now)
+	bucket.reserve(now)
+	if got := bucket.reserve(now.Add(100 * time.Millisecond)); got != 0 {
+		t.Errorf("恢复一个令牌后等待 %v", got)
+	}
+	if got := bucket.reserve(now.Add(time.Hour)); got != 0 || bucket.tokens != 1 {
+		t.Errorf("等待 %v, 剩余令牌 %v", got, bucket.tokens)
+	}
+
+	// 默认容量为速率向上取整
+	if bucket = newTokenBucket(RateLimit{Rate: 2.5}); bucket.burst != 3 {
+		t.Errorf("burst = %v", bucket.burst)
+	}
+}
+
+func TestRateLimiterPacing(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitConfig{RateLimit: RateLimit{Rate: 50, Burst: 1}})
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := limiter.wait(ctx, "appkey", "api/order"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 首次请求不等待，之后每20ms一次
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("6次请求耗时 %v, want >= 100ms", elapsed)
+	}
+}
+
+func TestRateLimiterGlobalAndEndpoint(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitConfig{
+		RateLimit: RateLimit{Rate: 10, Burst: 3},
+		Endpoints: map[string]RateLimit{"api/order": {Rate: 1, Burst: 1}},
+	})
+
+	if got := len(limiter.bucketsFor("appkey", "api/order")); got != 2 {
+		t.Errorf("api/order 令牌桶 %d 个, want 2", got)
+	}
+	if got := len(limiter.bucketsFor("appkey", "api/orderList")); got != 1 {
+		t.Errorf("api/orderList 令牌桶 %d 个, want 1", got)
+	}
+	// 不同appkey分别限流
+	if limiter.bucketsFor("appkey", "api/order")[0] == limiter.bucketsFor("other", "api/order")[0] {
+		t.Error("不同appkey使用了相同的令牌桶")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if wait, err := limiter.wait(ctx, "appkey", "api/order"); err != nil || wait != 0 {
+		t.Fatalf("首次请求 wait = %v, err = %v", wait, err)
+	}
+	// 接口限流需要等待1s，总限流还有令牌
+	if _, err := limiter.wait(ctx, "appkey", "api/order"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("接口限流: %v", err)
+	}
+	if wait, err := limiter.wait(ctx, "appkey", "api/orderList"); err != nil || wait != 0 {
+		t.Errorf("其他接口 wait = %v, err = %v", wait, err)
+	}
+
+	// 只配置接口限流
+	limiter = newRateLimiter(&RateLimitConfig{Endpoints: map[string]RateLimit{"api/order": {Rate: 1}}})
+	if got := len(limiter.bucketsFor("appkey", "api/orderList")); got != 0 {
+		t.Errorf("未限流的接口令牌桶 %d 个", got)
+	}
+}
+
+func TestRateLimiterCancelReturnsTokens(t *testing.T) {
+	limiter := newRateLimiter(&RateLimitConfig{
+		RateLimit: RateLimit{Rate: 1, Burst: 1},
+		Endpoints: map[string]RateLimit{"api/order": {Rate: 1, Burst: 1}},
+	})
+	if _, err := limiter.wait(context.Background(), "appkey", "api/order"); err != nil {
+		t.Fatal(err)
+	}
+	buckets := limiter.bucketsFor("appkey", "api/order")
+	before := []float64{buckets[0].tokens, buckets[1].tokens}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.wait(ctx, "appkey", "api/order"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v", err)
+	}
+	for i, bucket := range buckets {
+		// 取消后归还预约的令牌，期间恢复的令牌不超过1个
+		if bucket.tokens < before[i] || bucket.tokens > before[i]+1 {
+			t.Errorf("第%d个令牌桶 tokens = %v, 取消前 %v", i+1, bucket.tokens, before[i])
+		}
+	}
+	if stat := limiter.stats["api/order"]; stat.Requests != 1 {
+		t.Errorf("取消的请求不计入统计: %+v", stat)
+	}
+}
+
+func TestRateLimitStats(t *testing.T) {
+	c := newTestClient(t, okHandler())
+	if stats := c.RateLimitStats(); stats != nil {
+		t.Errorf("未配置限流时 RateLimitStats = %v", stats)
+	}
+
+	c.rateLimiter = newRateLimiter(&RateLimitConfig{RateLimit: RateLimit{Rate: 20, Burst: 1}})
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := c.ApiOrder(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.PoiCity(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := c.RateLimitStats()
+	order := stats["api/order"]
+	if order.Requests != 3 || order.Waits < 1 || order.Waits > 2 {
+		t.Errorf("api/order 统计 %+v", order)
+	}
+	if order.TotalWait <= 0 || order.MaxWait <= 0 || order.MaxWait > 50*time.Millisecond || order.TotalWait < order.MaxWait {
+		t.Errorf("api/order 等待时长 %+v", order)
+	}
+	if city := stats["poi/city"]; city.Requests != 1 {
+		t.Errorf("poi/city 统计 %+v", city)
+	}
+
+	// 返回的是副本
+	order.Requests = 100
+	if c.RateLimitStats()["api/order"].Requests != 3 {
+		t.Error("修改返回值影响了统计")
+	}
+}
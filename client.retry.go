@@ -0,0 +1,80 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/url"
+	"time"
+)
+
+// RetryConfig 重试配置
+type RetryConfig struct {
+	MaxAttempts    int           // 最大请求次数(包含首次请求)，小于等于1时不重试
+	InitialBackoff time.Duration // 首次重试等待时长，默认200ms
+	MaxBackoff     time.Duration // 最大重试等待时长，默认5s
+	Multiplier     float64       // 退避倍数，默认2
+	Jitter         float64       // 随机抖动比例(0-1)，默认0.2
+}
+
+// 补全默认值
+func (r RetryConfig) withDefaults() RetryConfig {
+	if r.MaxAttempts < 1 {
+		r.MaxAttempts = 1
+	}
+	if r.InitialBackoff <= 0 {
+		r.InitialBackoff = 200 * time.Millisecond
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 5 * time.Second
+	}
+	if r.Multiplier < 1 {
+		r.Multiplier = 2
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		r.Jitter = 0.2
+	}
+	return r
+}
+
+// 第 attempt 次请求失败后的等待时长，指数退避并加入随机抖动
+func (r RetryConfig) backoff(attempt int) time.Duration {
+	backoff := float64(r.InitialBackoff) * math.Pow(r.Multiplier, float64(attempt-1))
+	if backoff > float64(r.MaxBackoff) {
+		backoff = float64(r.MaxBackoff)
+	}
+	backoff += backoff * r.Jitter * (rand.Float64()*2 - 1)
+	return time.Duration(backoff)
+}
+
+// 是否需要重试，网络错误、服务端异常和可以重试的业务错误需要重试
+func shouldRetry(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if _, ok := AsAPIError(err); ok {
+		return IsRetryable(err)
+	}
+	// 网络错误
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// 等待重试，上下文取消时立即返回
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
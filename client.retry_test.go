@@ -0,0 +1,297 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 记录事件和状态的Span
+type recordingSpan struct {
+	embedded.Span
+	mu     sync.Mutex
+	name   string
+	events []string
+	errors []error
+	status codes.Code
+}
+
+func (s *recordingSpan) End(...trace.SpanEndOption)           {}
+func (s *recordingSpan) AddLink(trace.Link)                   {}
+func (s *recordingSpan) IsRecording() bool                    { return true }
+func (s *recordingSpan) SpanContext() trace.SpanContext       { return trace.SpanContext{} }
+func (s *recordingSpan) SetName(name string)                  {}
+func (s *recordingSpan) SetAttributes(...attribute.KeyValue)  {}
+func (s *recordingSpan) TracerProvider() trace.TracerProvider { return nil }
+func (s *recordingSpan) AddEvent(name string, _ ...trace.EventOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, name)
+}
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+}
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = code
+}
+
+// 记录创建的Span
+type recordingTracer struct {
+	embedded.Tracer
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+// 返回 recordingTracer 的 TracerProvider
+type recordingProvider struct {
+	embedded.TracerProvider
+	tracer *recordingTracer
+}
+
+func (p recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer { return p.tracer }
+
+func (tr *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{name: name}
+	tr.mu.Lock()
+	tr.spans = append(tr.spans, span)
+	tr.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+// 获取指定名称的Span，HTTP传输层的Span不在其中
+func (tr *recordingTracer) span(t *testing.T, name string) *recordingSpan {
+	t.Helper()
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	var found *recordingSpan
+	for _, span := range tr.spans {
+		if span.name == name {
+			if found != nil {
+				t.Fatalf("Span %s 重复", name)
+			}
+			found = span
+		}
+	}
+	if found == nil {
+		t.Fatalf("没有 Span %s", name)
+	}
+	return found
+}
+
+// 测试期间使用记录的 TracerProvider
+func useRecordingTracer(t *testing.T) *recordingTracer {
+	t.Helper()
+	prev := otel.GetTracerProvider()
+	tr := &recordingTracer{}
+	otel.SetTracerProvider(recordingProvider{tracer: tr})
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+	return tr
+}
+
+// 创建重试等待很短的实例
+func newRetryTestClient(t *testing.T, handler http.Handler, backoff time.Duration) *Client {
+	t.Helper()
+	c := newTestClient(t, handler)
+	c.retry = RetryConfig{MaxAttempts: 3, InitialBackoff: backoff, MaxBackoff: backoff}.withDefaults()
+	return c
+}
+
+func TestRetryRecomputesTimestampAndSign(t *testing.T) {
+	var ts atomic.Int64
+	ts.Store(1700000000)
+	prev := requestTimestamp
+	requestTimestamp = func() int64 { return ts.Add(1) }
+	t.Cleanup(func() { requestTimestamp = prev })
+
+	var (
+		mu      sync.Mutex
+		queries []url.Values
+	)
+	c := newRetryTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Query())
+		n := len(queries)
+		mu.Unlock()
+		if n < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"dataList":[],"total":0}`))
+	}), time.Millisecond)
+
+	if _, err := c.ApiOrderList(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) != 3 {
+		t.Fatalf("请求次数 %d, want 3", len(queries))
+	}
+	seen := make(map[string]bool)
+	for i, query := range queries {
+		params := make(map[string]any)
+		for k := range query {
+			params[k] = query.Get(k)
+		}
+		if !c.VerifySign(params, query.Get("sign")) {
+			t.Errorf("第%d次请求签名错误: %v", i+1, query)
+		}
+		if seen[query.Get("ts")] || seen[query.Get("sign")] {
+			t.Errorf("第%d次请求沿用了之前的 ts 或 sign: %v", i+1, query)
+		}
+		seen[query.Get("ts")], seen[query.Get("sign")] = true, true
+	}
+}
+
+func TestRetryRecomputesCpsSignature(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		timestamps []string
+	)
+	c := newRetryTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		timestamps = append(timestamps, r.Header.Get(HeaderCaTimestamp))
+		n := len(timestamps)
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0}`))
+	}), 5*time.Millisecond)
+
+	if _, err := c.CpsOpenGetReferralLink(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(timestamps) != 2 || timestamps[0] == timestamps[1] {
+		t.Errorf("CPS 请求时间戳 %v", timestamps)
+	}
+}
+
+func TestRetryClassification(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		attempts int32
+		wantErr  bool
+	}{
+		{
+			name:     "服务端异常重试",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusInternalServerError) },
+			attempts: 3,
+			wantErr:  true,
+		},
+		{
+			name: "网络错误重试",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					_ = conn.Close()
+				}
+			},
+			attempts: 3,
+			wantErr:  true,
+		},
+		{
+			name:     "客户端错误不重试",
+			handler:  func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadRequest) },
+			attempts: 1,
+			wantErr:  true,
+		},
+		{
+			name: "业务错误不重试",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"status":1,"des":"参数错误"}`))
+			},
+			attempts: 1,
+			wantErr:  true,
+		},
+		{
+			name:     "成功",
+			handler:  func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte(`{"status":0}`)) },
+			attempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			c := newRetryTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				tt.handler(w, r)
+			}), time.Millisecond)
+			_, err := c.ApiOrder(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v", err)
+			}
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("请求次数 %d, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestRetrySpanEvents(t *testing.T) {
+	tr := useRecordingTracer(t)
+	c := newRetryTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}), time.Millisecond)
+
+	if _, err := c.ApiOrder(context.Background()); err == nil {
+		t.Fatal("应返回错误")
+	}
+	span := tr.span(t, "meituan.api/order")
+	retries := 0
+	for _, event := range span.events {
+		if event == "meituan.retry" {
+			retries++
+		}
+	}
+	if retries != 2 {
+		t.Errorf("重试事件 %d 个, want 2: %v", retries, span.events)
+	}
+	if span.status != codes.Error || len(span.errors) != 1 {
+		t.Errorf("status = %v, errors = %v", span.status, span.errors)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	tr := useRecordingTracer(t)
+	var attempts atomic.Int32
+	c := newRetryTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}), time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	_, err := c.ApiOrder(ctx)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("取消后没有停止重试，耗时 %v", elapsed)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.HttpStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("err = %v", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("请求次数 %d, want 1", got)
+	}
+
+	// 等待重试时取消也记录错误状态
+	span := tr.span(t, "meituan.api/order")
+	if span.status != codes.Error || len(span.errors) != 1 {
+		t.Errorf("status = %v, errors = %v", span.status, span.errors)
+	}
+}
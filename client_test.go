@@ -0,0 +1,90 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gorequest"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// 接口调用，用于遍历所有公开的接口方法
+type testAPICall struct {
+	name string
+	call func(ctx context.Context, c *Client) error
+}
+
+var testAPICalls = []testAPICall{
+	{"ApiOrderList", func(ctx context.Context, c *Client) error { _, err := c.ApiOrderList(ctx); return err }},
+	{"ApiOrder", func(ctx context.Context, c *Client) error { _, err := c.ApiOrder(ctx); return err }},
+	{"ApiGenerateLink", func(ctx context.Context, c *Client) error { _, err := c.ApiGenerateLink(ctx); return err }},
+	{"ApiMiniCode", func(ctx context.Context, c *Client) error { _, err := c.ApiMiniCode(ctx); return err }},
+	{"ApiGetQuaLitYsCoreBySid", func(ctx context.Context, c *Client) error { _, err := c.ApiGetQuaLitYsCoreBySid(ctx); return err }},
+	{"ApiMtUnionCity", func(ctx context.Context, c *Client) error { _, err := c.ApiMtUnionCity(ctx); return err }},
+	{"ApiMtUnionCategory", func(ctx context.Context, c *Client) error { _, err := c.ApiMtUnionCategory(ctx); return err }},
+	{"ApiMtUnionSku", func(ctx context.Context, c *Client) error { _, err := c.ApiMtUnionSku(ctx); return err }},
+	{"ApiMtUnionPoi", func(ctx context.Context, c *Client) error { _, err := c.ApiMtUnionPoi(ctx); return err }},
+	{"PoiCity", func(ctx context.Context, c *Client) error { _, err := c.PoiCity(ctx); return err }},
+	{"PoiDistrict", func(ctx context.Context, c *Client) error { _, err := c.PoiDistrict(ctx, 1); return err }},
+	{"PoiArea", func(ctx context.Context, c *Client) error { _, err := c.PoiArea(ctx, 1); return err }},
+	{"PoiCategory", func(ctx context.Context, c *Client) error { _, err := c.PoiCategory(ctx, 1); return err }},
+	{"CpsOpenGetReferralLink", func(ctx context.Context, c *Client) error { _, err := c.CpsOpenGetReferralLink(ctx); return err }},
+}
+
+// 创建请求本地测试服务的实例
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c, err := NewClient(&ClientConfig{
+		Secret:     "secret",
+		AppKey:     "appkey",
+		BaseURL:    server.URL + "/",
+		CpsBaseURL: server.URL + "/",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// 所有接口都返回成功
+func okHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"status":0,"code":0}`))
+	})
+}
+
+// 需要配合 go test -race 运行
+func TestClientConcurrentCalls(t *testing.T) {
+	c := newTestClient(t, okHandler())
+	c.SetLogFun(func(ctx context.Context, response *gorequest.LogResponse) {})
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, api := range testAPICalls {
+			wg.Add(1)
+			go func(api testAPICall) {
+				defer wg.Done()
+				if err := api.call(ctx, c); err != nil {
+					t.Errorf("%s: %v", api.name, err)
+				}
+			}(api)
+		}
+
+		// 请求的同时修改配置
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.SetSecret("secret" + strconv.Itoa(i))
+			c.SetAppKey("appkey" + strconv.Itoa(i))
+			c.SetClientIP("127.0.0.1")
+			c.SetTrace(i%2 == 0)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,35 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gorequest"
+)
+
+type CpsOpenGetReferralLinkResponse struct {
+	Code    int    `json:"code"`              // 响应码，0成功，其他失败
+	Message string `json:"message,omitempty"` // 响应文案
+	Data    string `json:"data,omitempty"`    // 推广链接
+}
+
+type CpsOpenGetReferralLinkResult struct {
+	Result CpsOpenGetReferralLinkResponse // 结果
+	Body   []byte                         // 内容
+	Http   gorequest.Response             // 请求
+}
+
+func newCpsOpenGetReferralLinkResult(result CpsOpenGetReferralLinkResponse, body []byte, http gorequest.Response) *CpsOpenGetReferralLinkResult {
+	return &CpsOpenGetReferralLinkResult{Result: result, Body: body, Http: http}
+}
+
+// CpsOpenGetReferralLink 获取推广链接（CPS开放接口）
+// 使用 S-Ca-App、S-Ca-Timestamp、S-Ca-Signature 请求头签名
+// https://page.meituan.net/html/1687318722216_edeb3f/index.html
+func (c *Client) CpsOpenGetReferralLink(ctx context.Context, notMustParams ...gorequest.Params) (*CpsOpenGetReferralLinkResult, error) {
+
+	// 参数
+	params := gorequest.NewParamsWith(notMustParams...)
+
+	// 请求
+	response, request, err := call[CpsOpenGetReferralLinkResponse](ctx, c, endpointCpsOpenGetReferralLink, params)
+	return newCpsOpenGetReferralLinkResult(response, request.ResponseBody, request), err
+}
@@ -0,0 +1,182 @@
+package meituan
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrInvalidRequest 请求参数校验失败
+var ErrInvalidRequest = errors.New("请求参数错误")
+
+// ErrCallbackSign 订单回推签名错误
+var ErrCallbackSign = errors.New("订单回推签名错误")
+
+// ErrCallbackAppKey 订单回推的appkey不在允许范围内
+var ErrCallbackAppKey = errors.New("订单回推appkey不允许")
+
+// CallbackError 订单回推校验失败，Err 为 ErrCallbackSign 或 ErrCallbackAppKey
+type CallbackError struct {
+	OrderID string // 订单id
+	AppKey  string // 回推的appkey
+	Err     error  // 失败原因
+}
+
+func (e *CallbackError) Error() string {
+	return fmt.Sprintf("%s: orderid=%s appkey=%s", e.Err, e.OrderID, e.AppKey)
+}
+
+func (e *CallbackError) Unwrap() error {
+	return e.Err
+}
+
+// APIError 美团接口返回的业务错误
+// 联盟接口 status 非0、开放平台接口 code 非0 或者 HTTP 状态码异常时返回
+type APIError struct {
+	Endpoint       string // 接口路径
+	Code           int    // 业务状态值，联盟接口为 status，开放平台接口为 code
+	Message        string // 异常描述信息，联盟接口为 des，开放平台接口为 msg，CPS开放接口为 message
+	HttpStatusCode int    // HTTP状态码
+	Body           []byte // 返回内容
+
+	kinds errorKind // 按 ClientConfig.ErrorCodes 识别的分类
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 && e.HttpStatusCode != 0 {
+		return fmt.Sprintf("美团接口 %s 请求失败: http status %d", e.Endpoint, e.HttpStatusCode)
+	}
+	return fmt.Sprintf("美团接口 %s 返回错误: code=%d message=%s", e.Endpoint, e.Code, e.Message)
+}
+
+// 根据返回内容创建业务错误，业务成功时返回 nil
+func newAPIError(ep endpoint, httpStatusCode int, body []byte, envelope responseEnvelope, classifier errorClassifier) *APIError {
+	apiErr := &APIError{
+		Endpoint:       ep.path,
+		HttpStatusCode: httpStatusCode,
+		Body:           body,
+	}
+	switch {
+	case ep.envelope == envelopeStatus && envelope.Status != nil && *envelope.Status != 0:
+		apiErr.Code, apiErr.Message = *envelope.Status, envelope.Des
+	case ep.envelope == envelopeCode && envelope.Code != nil && *envelope.Code != 0:
+		apiErr.Code, apiErr.Message = *envelope.Code, envelope.Msg
+		if apiErr.Message == "" {
+			apiErr.Message = envelope.Message
+		}
+	case httpStatusCode >= http.StatusBadRequest:
+		apiErr.Message = http.StatusText(httpStatusCode)
+	default:
+		return nil
+	}
+	if apiErr.Code != 0 {
+		apiErr.kinds = classifier[apiErr.Code]
+	}
+	return apiErr
+}
+
+// AsAPIError 获取业务错误
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	ok := errors.As(err, &apiErr)
+	return apiErr, ok
+}
+
+// ErrorCodes 业务错误码分类，值为联盟接口的 status 或开放平台接口的 code
+// 美团没有公开统一的错误码表，默认不按错误码分类，只按HTTP状态码和异常描述信息识别
+type ErrorCodes struct {
+	RateLimited      []int // 请求过于频繁
+	SignError        []int // 签名错误
+	TimestampExpired []int // 时间戳过期
+	Retryable        []int // 可以重试
+}
+
+// 错误分类
+type errorKind uint8
+
+const (
+	errorRateLimited errorKind = 1 << iota
+	errorSign
+	errorTimestampExpired
+	errorRetryable
+)
+
+// 错误码对应的分类，创建实例后不再修改
+type errorClassifier map[int]errorKind
+
+func newErrorClassifier(codes ErrorCodes) errorClassifier {
+	classifier := make(errorClassifier)
+	for kind, list := range map[errorKind][]int{
+		errorRateLimited:      codes.RateLimited,
+		errorSign:             codes.SignError,
+		errorTimestampExpired: codes.TimestampExpired,
+		errorRetryable:        codes.Retryable,
+	} {
+		for _, code := range list {
+			classifier[code] |= kind
+		}
+	}
+	return classifier
+}
+
+// 错误码无法识别时使用的异常描述信息，只匹配明确的短语
+var (
+	rateLimitedMessages      = []string{"请求过于频繁", "请求太频繁", "访问过于频繁", "qps超限", "too many requests", "rate limit exceeded"}
+	signErrorMessages        = []string{"签名错误", "签名验证失败", "签名校验失败", "签名不正确", "sign error", "invalid sign", "signature mismatch"}
+	timestampExpiredMessages = []string{"时间戳已过期", "时间戳过期", "ts已过期", "ts过期", "timestamp expired"}
+	retryableMessages        = []string{"系统繁忙", "服务繁忙", "请稍后重试", "服务暂不可用", "service unavailable"}
+)
+
+// 异常描述信息是否包含任意一个短语
+func (e *APIError) messageContains(phrases []string) bool {
+	message := strings.ToLower(e.Message)
+	for _, phrase := range phrases {
+		if strings.Contains(message, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// 按错误码分类，错误码没有分类时使用异常描述信息
+func classifyAPIError(err error, kind errorKind, messages []string) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.kinds&kind != 0 {
+		return true
+	}
+	return apiErr.messageContains(messages)
+}
+
+// IsRateLimited 是否请求过于频繁被限流
+func IsRateLimited(err error) bool {
+	if apiErr, ok := AsAPIError(err); ok && apiErr.HttpStatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return classifyAPIError(err, errorRateLimited, rateLimitedMessages)
+}
+
+// IsSignError 是否签名错误
+func IsSignError(err error) bool {
+	return classifyAPIError(err, errorSign, signErrorMessages)
+}
+
+// IsTimestampExpired 是否请求时间戳(ts)已过期，ts 有效期为60s
+func IsTimestampExpired(err error) bool {
+	return classifyAPIError(err, errorTimestampExpired, timestampExpiredMessages)
+}
+
+// IsRetryable 是否可以重试，限流、时间戳过期、系统繁忙和服务端异常可以重试
+func IsRetryable(err error) bool {
+	apiErr, ok := AsAPIError(err)
+	if !ok {
+		return false
+	}
+	if apiErr.HttpStatusCode >= http.StatusInternalServerError {
+		return true
+	}
+	return IsRateLimited(err) || IsTimestampExpired(err) || classifyAPIError(err, errorRetryable, retryableMessages)
+}
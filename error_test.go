@@ -0,0 +1,106 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIErrorClassify(t *testing.T) {
+	classifier := newErrorClassifier(ErrorCodes{
+		RateLimited:      []int{1001},
+		SignError:        []int{1002},
+		TimestampExpired: []int{1003},
+		Retryable:        []int{1004, 1001},
+	})
+	// 按配置的错误码创建业务错误
+	coded := func(code int, message string) *APIError {
+		status := code
+		return newAPIError(endpointApiOrder, http.StatusOK, nil, responseEnvelope{Status: &status, Des: message}, classifier)
+	}
+	tests := []struct {
+		name             string
+		err              error
+		rateLimited      bool
+		signError        bool
+		timestampExpired bool
+		retryable        bool
+	}{
+		{name: "非业务错误", err: errors.New("请求过于频繁")},
+		{name: "限流错误码", err: coded(1001, ""), rateLimited: true, retryable: true},
+		{name: "未配置的错误码", err: coded(429, "")},
+		{name: "HTTP 429", err: &APIError{HttpStatusCode: http.StatusTooManyRequests}, rateLimited: true, retryable: true},
+		{name: "限流描述", err: coded(1, "请求过于频繁，请稍后"), rateLimited: true, retryable: true},
+		{name: "签名错误码", err: coded(1002, ""), signError: true},
+		{name: "签名描述", err: coded(1, "签名验证失败"), signError: true},
+		{name: "包含sign的其他错误", err: coded(1, "signType参数缺失")},
+		{name: "时间戳过期错误码", err: coded(1003, ""), timestampExpired: true, retryable: true},
+		{name: "时间戳过期描述", err: coded(1, "ts已过期"), timestampExpired: true, retryable: true},
+		{name: "可重试错误码", err: coded(1004, ""), retryable: true},
+		{name: "HTTP 5xx", err: &APIError{HttpStatusCode: http.StatusBadGateway}, retryable: true},
+		{name: "系统繁忙", err: coded(1, "系统繁忙"), retryable: true},
+		{name: "系统参数错误不重试", err: coded(1, "系统参数错误")},
+		{name: "包装后的错误", err: fmt.Errorf("查询失败: %w", coded(1001, "")), rateLimited: true, retryable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRateLimited(tt.err); got != tt.rateLimited {
+				t.Errorf("IsRateLimited = %v, want %v", got, tt.rateLimited)
+			}
+			if got := IsSignError(tt.err); got != tt.signError {
+				t.Errorf("IsSignError = %v, want %v", got, tt.signError)
+			}
+			if got := IsTimestampExpired(tt.err); got != tt.timestampExpired {
+				t.Errorf("IsTimestampExpired = %v, want %v", got, tt.timestampExpired)
+			}
+			if got := IsRetryable(tt.err); got != tt.retryable {
+				t.Errorf("IsRetryable = %v, want %v", got, tt.retryable)
+			}
+		})
+	}
+}
+
+func TestClientErrorCodes(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":1001,"des":"失败"}`))
+	})
+
+	// 默认不按错误码分类
+	c := newTestClient(t, handler)
+	if _, err := c.ApiOrder(context.Background()); err == nil || IsRateLimited(err) {
+		t.Errorf("默认配置: %v", err)
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := &ClientConfig{Secret: "secret", AppKey: "appkey", BaseURL: server.URL + "/", ErrorCodes: ErrorCodes{RateLimited: []int{1001}}}
+	c, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 创建实例后修改配置不影响分类
+	config.ErrorCodes.RateLimited[0] = 1
+	if _, err = c.ApiOrder(context.Background()); !IsRateLimited(err) {
+		t.Errorf("配置错误码: %v", err)
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	status, code := 1, 2
+	if err := newAPIError(endpointApiOrder, http.StatusOK, nil, responseEnvelope{Status: &status, Des: "参数错误"}, nil); err == nil || err.Code != 1 || err.Message != "参数错误" {
+		t.Errorf("status 信封: %v", err)
+	}
+	if err := newAPIError(endpointPoiCity, http.StatusOK, nil, responseEnvelope{Code: &code, Message: "失败"}, nil); err == nil || err.Code != 2 || err.Message != "失败" {
+		t.Errorf("code 信封: %v", err)
+	}
+	zero := 0
+	if err := newAPIError(endpointApiOrder, http.StatusOK, nil, responseEnvelope{Status: &zero}, nil); err != nil {
+		t.Errorf("成功返回: %v", err)
+	}
+	if err := newAPIError(endpointApiOrder, http.StatusBadGateway, nil, responseEnvelope{}, nil); err == nil || err.HttpStatusCode != http.StatusBadGateway {
+		t.Errorf("HTTP 异常: %v", err)
+	}
+}
@@ -3,10 +3,12 @@ module go.dtapp.net/meituan
 go 1.22.3
 
 require (
+	github.com/mattn/go-sqlite3 v1.14.22
 	go.dtapp.net/gojson v1.0.4
 	go.dtapp.net/gorequest v1.0.65
-	go.dtapp.net/gostring v1.0.15
 	go.dtapp.net/gotime v1.0.11
+	go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace v0.52.0
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.52.0
 	go.opentelemetry.io/otel v1.27.0
 	go.opentelemetry.io/otel/trace v1.27.0
 )
@@ -28,9 +30,8 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	go.dtapp.net/gorandom v1.0.3 // indirect
+	go.dtapp.net/gostring v1.0.15 // indirect
 	go.dtapp.net/gourl v1.0.0 // indirect
-	go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace v0.52.0 // indirect
-	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.52.0 // indirect
 	go.opentelemetry.io/otel/metric v1.27.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
 	golang.org/x/net v0.26.0 // indirect
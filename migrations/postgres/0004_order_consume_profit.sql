@@ -0,0 +1 @@
+ALTER TABLE meituan_order ADD COLUMN IF NOT EXISTS consume_profit BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS meituan_callback_dedup_created_at ON meituan_callback_dedup (created_at);
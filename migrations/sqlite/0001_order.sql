@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS meituan_order (
+    order_id          TEXT    NOT NULL PRIMARY KEY,
+    source            TEXT    NOT NULL DEFAULT '',
+    app_key           TEXT    NOT NULL DEFAULT '',
+    sid               TEXT    NOT NULL DEFAULT '',
+    uid               TEXT    NOT NULL DEFAULT '',
+    act_id            INTEGER NOT NULL DEFAULT 0,
+    business_line     INTEGER NOT NULL DEFAULT 0,
+    sub_business_line INTEGER NOT NULL DEFAULT 0,
+    type              TEXT    NOT NULL DEFAULT '',
+    title             TEXT    NOT NULL DEFAULT '',
+    product_id        TEXT    NOT NULL DEFAULT '',
+    product_name      TEXT    NOT NULL DEFAULT '',
+    quantity          INTEGER NOT NULL DEFAULT 0,
+    status            INTEGER NOT NULL DEFAULT 0,
+    risk              INTEGER NOT NULL DEFAULT 0,
+    trade_types       TEXT    NOT NULL DEFAULT '',
+    order_time        INTEGER NOT NULL DEFAULT 0,
+    pay_time          INTEGER NOT NULL DEFAULT 0,
+    mod_time          INTEGER NOT NULL DEFAULT 0,
+    refund_time       INTEGER NOT NULL DEFAULT 0,
+    total             INTEGER NOT NULL DEFAULT 0,
+    pay_price         INTEGER NOT NULL DEFAULT 0,
+    profit            INTEGER NOT NULL DEFAULT 0,
+    cpa_profit        INTEGER NOT NULL DEFAULT 0,
+    refund_price      INTEGER NOT NULL DEFAULT 0,
+    refund_profit     INTEGER NOT NULL DEFAULT 0,
+    cpa_refund_profit INTEGER NOT NULL DEFAULT 0,
+    coupon_code       TEXT    NOT NULL DEFAULT '',
+    updated_at        INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS meituan_order_sid_pay_time ON meituan_order (sid, pay_time);
+CREATE INDEX IF NOT EXISTS meituan_order_pay_time ON meituan_order (pay_time);
+CREATE INDEX IF NOT EXISTS meituan_order_status ON meituan_order (status);
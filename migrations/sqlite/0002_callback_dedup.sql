@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS meituan_callback_dedup (
+    dedup_key  TEXT    NOT NULL PRIMARY KEY,
+    created_at INTEGER NOT NULL DEFAULT 0
+);
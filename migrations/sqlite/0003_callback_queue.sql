@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS meituan_callback_queue (
+    id         TEXT    NOT NULL PRIMARY KEY,
+    state      TEXT    NOT NULL DEFAULT '',
+    params     TEXT    NOT NULL DEFAULT '',
+    raw        TEXT    NOT NULL DEFAULT '',
+    attempts   INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT    NOT NULL DEFAULT '',
+    created_at INTEGER NOT NULL DEFAULT 0,
+    updated_at INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS meituan_callback_queue_state ON meituan_callback_queue (state, created_at);
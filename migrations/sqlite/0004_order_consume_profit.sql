@@ -0,0 +1 @@
+ALTER TABLE meituan_order ADD COLUMN consume_profit INTEGER NOT NULL DEFAULT 0;
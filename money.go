@@ -0,0 +1,187 @@
+package meituan
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Money 金额，以整数分保存，避免浮点数计算误差
+// JSON 解析时支持数字和字符串，单位为元；JSON 输出为两位小数的元字符串，例如 "12.34"
+// 解析时超过分的精度按四舍五入（远离零）保留到分，例如 0.125 元为 13 分，-0.125 元为 -13 分
+type Money int64
+
+var (
+	yuanPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`) // 元
+	fenPattern  = regexp.MustCompile(`^-?\d+(\.\d+)?$`) // 分
+)
+
+// 按格式解析十进制数字并乘以倍数，结果四舍五入到整数，不支持分数、十六进制和科学计数法
+func parseDecimal(s string, pattern *regexp.Regexp, scale int64) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if !pattern.MatchString(s) {
+		return 0, fmt.Errorf("金额格式错误: %q", s)
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("金额格式错误: %q", s)
+	}
+	r.Mul(r, new(big.Rat).SetInt64(scale))
+	if new(big.Int).Quo(r.Num(), r.Denom()).CmpAbs(big.NewInt(math.MaxInt64)) >= 0 {
+		return 0, fmt.Errorf("金额超出范围: %q", s)
+	}
+	return roundRat(r), nil
+}
+
+// ParseYuan 解析单位为元的金额字符串，例如 12.34、-0.5、100，超过两位小数时四舍五入到分，空字符串为0
+func ParseYuan(s string) (Money, error) {
+	fen, err := parseDecimal(s, yuanPattern, 100)
+	return Money(fen), err
+}
+
+// ParseFen 解析单位为分的金额字符串，例如 1234，有小数时四舍五入到分，空字符串为0
+func ParseFen(s string) (Money, error) {
+	fen, err := parseDecimal(s, fenPattern, 1)
+	return Money(fen), err
+}
+
+// Fen 单位为分的金额
+func (m Money) Fen() int64 {
+	return int64(m)
+}
+
+// Yuan 单位为元的金额字符串，保留两位小数
+func (m Money) Yuan() string {
+	return m.String()
+}
+
+// String 单位为元的金额字符串，保留两位小数
+func (m Money) String() string {
+	fen := int64(m)
+	sign := ""
+	if fen < 0 {
+		sign = "-"
+	}
+	abs := new(big.Int).Abs(big.NewInt(fen))
+	yuan, rem := new(big.Int).QuoRem(abs, big.NewInt(100), new(big.Int))
+	return fmt.Sprintf("%s%s.%02d", sign, yuan, rem.Int64())
+}
+
+// Add 加
+func (m Money) Add(o Money) Money {
+	return m + o
+}
+
+// Sub 减
+func (m Money) Sub(o Money) Money {
+	return m - o
+}
+
+// Mul 乘以整数
+func (m Money) Mul(n int64) Money {
+	return m * Money(n)
+}
+
+// MulRatio 乘以比例 numerator/denominator，结果四舍五入到分
+func (m Money) MulRatio(numerator, denominator int64) Money {
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(numerator)), big.NewInt(denominator))
+	return Money(roundRat(r))
+}
+
+// 四舍五入，远离零
+func roundRat(r *big.Rat) int64 {
+	num, den := new(big.Int).Set(r.Num()), r.Denom()
+	negative := num.Sign() < 0
+	num.Abs(num)
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if negative {
+		quo.Neg(quo)
+	}
+	return quo.Int64()
+}
+
+// Neg 取反
+func (m Money) Neg() Money {
+	return -m
+}
+
+// Abs 绝对值
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// IsZero 是否为0
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// Cmp 比较，小于返回-1，等于返回0，大于返回1
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m < o:
+		return -1
+	case m > o:
+		return 1
+	}
+	return 0
+}
+
+// 获取 JSON 中的数字或字符串内容
+func jsonNumberText(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return ""
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		return s
+	}
+	return string(data)
+}
+
+// UnmarshalJSON 解析单位为元的数字或字符串
+func (m *Money) UnmarshalJSON(data []byte) error {
+	v, err := ParseYuan(jsonNumberText(data))
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
+
+// MarshalJSON 输出单位为元的字符串
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(m.String())), nil
+}
+
+// FenMoney 接口中以分为单位的金额，JSON 解析和输出均为分
+type FenMoney struct {
+	Money
+}
+
+// UnmarshalJSON 解析单位为分的数字或字符串
+func (m *FenMoney) UnmarshalJSON(data []byte) error {
+	v, err := ParseFen(jsonNumberText(data))
+	if err != nil {
+		return err
+	}
+	m.Money = v
+	return nil
+}
+
+// MarshalJSON 输出单位为分的字符串
+func (m FenMoney) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(strconv.FormatInt(m.Fen(), 10))), nil
+}
@@ -0,0 +1,169 @@
+package meituan
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseYuan(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "12.34", want: 1234},
+		{in: " 12.3 ", want: 1230},
+		{in: "100", want: 10000},
+		{in: "-0.5", want: -50},
+		{in: "0.01", want: 1},
+		{in: "0.001", want: 0},
+		{in: "0.005", want: 1},
+		{in: "0.124", want: 12},
+		{in: "0.125", want: 13},
+		{in: "-0.125", want: -13},
+		{in: "1.999", want: 200},
+		{in: "1/2", err: true},
+		{in: "0x10", err: true},
+		{in: "1e2", err: true},
+		{in: "+1", err: true},
+		{in: ".5", err: true},
+		{in: "1.", err: true},
+		{in: "abc", err: true},
+		{in: "99999999999999999999", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseYuan(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseYuan(%q) err = %v, want err %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseYuan(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFen(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  bool
+	}{
+		{in: "", want: 0},
+		{in: "1234", want: 1234},
+		{in: "-5", want: -5},
+		{in: "12.5", want: 13},
+		{in: "12.49", want: 12},
+		{in: "-12.5", want: -13},
+		{in: "1.", err: true},
+		{in: "1/2", err: true},
+		{in: "0x10", err: true},
+		{in: "1e2", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFen(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseFen(%q) err = %v, want err %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFen(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := map[Money]string{0: "0.00", 5: "0.05", 1234: "12.34", -50: "-0.50", -1234: "-12.34"}
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+		// 输出的字符串可以原样解析
+		if back, err := ParseYuan(m.String()); err != nil || back != m {
+			t.Errorf("ParseYuan(%q) = %d, %v", m.String(), back, err)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		Yuan Money    `json:"yuan"`
+		Str  Money    `json:"str"`
+		Null Money    `json:"null"`
+		Fen  FenMoney `json:"fen"`
+	}
+	if err := json.Unmarshal([]byte(`{"yuan":12.5,"str":"-0.01","null":null,"fen":1234}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if v.Yuan != 1250 || v.Str != -1 || v.Null != 0 || v.Fen.Money != 1234 {
+		t.Errorf("解析结果 %+v", v)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"yuan":"12.50","str":"-0.01","null":"0.00","fen":"1234"}`; string(data) != want {
+		t.Errorf("输出 %s, want %s", data, want)
+	}
+
+	var back struct {
+		Yuan Money    `json:"yuan"`
+		Str  Money    `json:"str"`
+		Null Money    `json:"null"`
+		Fen  FenMoney `json:"fen"`
+	}
+	if err = json.Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back != v {
+		t.Errorf("往返结果 %+v, want %+v", back, v)
+	}
+
+	for _, data := range []string{`{"yuan":1e2}`, `{"yuan":"1/2"}`, `{"fen":"0x10"}`, `{"fen":"1e2"}`} {
+		if err = json.Unmarshal([]byte(data), &back); err == nil {
+			t.Errorf("%s 应解析失败", data)
+		}
+	}
+}
+
+func TestMoneyExtraPrecision(t *testing.T) {
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "sku") {
+			_, _ = w.Write([]byte(`{"code":0,"data":{"dataList":[{"skuId":"1","price":1990},{"skuId":"2","price":"1990.5"}],"total":2}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":{"dataList":[{"poiViewId":"1","shippingFee":"0.125","minPrice":20},{"poiViewId":"2","shippingFee":"1.5","minPrice":"15.004"}]}}`))
+	}))
+	ctx := context.Background()
+
+	// 超过分的精度的金额不影响整个列表的解析
+	poi, err := c.ApiMtUnionPoi(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := poi.Result.Data.DataList
+	if len(list) != 2 || list[0].ShippingFee != 13 || list[0].MinPrice != 2000 || list[1].ShippingFee != 150 || list[1].MinPrice != 1500 {
+		t.Errorf("门店列表 %+v", list)
+	}
+	sku, err := c.ApiMtUnionSku(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skus := sku.Result.Data.DataList; len(skus) != 2 || skus[0].Price.Money != 1990 || skus[1].Price.Money != 1991 {
+		t.Errorf("商品列表 %+v", skus)
+	}
+
+	order, err := ApiOrderListOrder{Orderid: "1", Payprice: "10.005", Profit: "0.125"}.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.PayPrice != 1001 || order.Profit != 13 {
+		t.Errorf("订单金额 payprice=%s profit=%s", order.PayPrice, order.Profit)
+	}
+}
@@ -0,0 +1,255 @@
+package meituan
+
+import (
+	"sync"
+)
+
+// OrderEventType 订单生命周期事件类型
+type OrderEventType string
+
+const (
+	OrderPaid              OrderEventType = "paid"                // 订单已付款，Before.Status 与 After.Status 为变化前后的状态
+	OrderCompleted         OrderEventType = "completed"           // 订单已完成
+	OrderRefunded          OrderEventType = "refunded"            // 订单已全部退款
+	OrderPartiallyRefunded OrderEventType = "partially_refunded"  // 订单部分退款，Before.RefundPrice 与 After.RefundPrice 为变化前后的退款金额
+	OrderMarkedRisk        OrderEventType = "marked_risk"         // 订单被标记为风控订单
+	CommissionAdjusted     OrderEventType = "commission_adjusted" // 订单佣金调整，Before.Commission() 与 After.Commission() 为变化前后的佣金
+)
+
+// OrderSource 订单来源
+type OrderSource string
+
+const (
+	OrderSourceList     OrderSource = "list"     // 订单列表查询接口
+	OrderSourceQuery    OrderSource = "query"    // 单订单查询接口
+	OrderSourceCallback OrderSource = "callback" // 订单回推
+)
+
+// OrderEvent 订单生命周期事件
+type OrderEvent struct {
+	Type   OrderEventType // 事件类型
+	Before Order          // 变化前的订单，首次出现的订单为零值
+	After  Order          // 变化后的订单
+}
+
+// Commission 订单预估佣金，返佣金额与cpa收益之和扣除退款需要扣除的部分
+func (o Order) Commission() Money {
+	return o.Profit.Add(o.CpaProfit).Sub(o.RefundProfit).Sub(o.CpaRefundProfit)
+}
+
+// 订单来源是否返回佣金、退款和风控信息，订单回推不返回这些字段
+func (o Order) hasSettlement() bool {
+	return o.Source != OrderSourceCallback
+}
+
+// 订单状态的先后顺序，已付款、已完成、已退款或风控依次推进，其他状态为0
+func orderStatusRank(status OrderStatus) int {
+	switch status {
+	case OrderStatusPaid:
+		return 1
+	case OrderStatusCompleted:
+		return 2
+	case OrderStatusRefunded:
+		return 3
+	}
+	return 0
+}
+
+// 没有修改时间的版本是否比 prev 更新，状态推进或退款金额增加
+func isOrderAhead(prev Order, next Order) bool {
+	if rank, prevRank := orderStatusRank(next.Status), orderStatusRank(prev.Status); rank != prevRank {
+		return rank > prevRank
+	}
+	return next.hasSettlement() && next.RefundPrice.Cmp(prev.RefundPrice) > 0
+}
+
+// 订单回推不返回佣金、退款和风控信息，沿用上一个版本的这些字段
+func mergeSettlement(prev *Order, next Order) Order {
+	if prev == nil || next.hasSettlement() {
+		return next
+	}
+	next.Risk = prev.Risk
+	next.RefundTime = prev.RefundTime
+	next.Profit = prev.Profit
+	next.CpaProfit = prev.CpaProfit
+	next.RefundPrice = prev.RefundPrice
+	next.RefundProfit = prev.RefundProfit
+	next.CpaRefundProfit = prev.CpaRefundProfit
+	next.ConsumeProfit = prev.ConsumeProfit
+	return next
+}
+
+// 使用 next 的佣金、退款和风控信息更新 prev，其他字段保持不变
+func applySettlement(prev Order, next Order) Order {
+	prev.Source = next.Source
+	prev.Risk = next.Risk
+	prev.RefundTime = next.RefundTime
+	prev.Profit = next.Profit
+	prev.CpaProfit = next.CpaProfit
+	prev.RefundPrice = next.RefundPrice
+	prev.RefundProfit = next.RefundProfit
+	prev.CpaRefundProfit = next.CpaRefundProfit
+	prev.ConsumeProfit = next.ConsumeProfit
+	return prev
+}
+
+// 只有部分来源返回的字段，新版本没有时沿用上一个版本
+func fillMissing(prev Order, next Order) Order {
+	if next.Uid == "" {
+		next.Uid = prev.Uid
+	}
+	if next.Type == "" {
+		next.Type = prev.Type
+	}
+	if next.Quantity == 0 {
+		next.Quantity = prev.Quantity
+	}
+	if next.OrderTime.IsZero() {
+		next.OrderTime = prev.OrderTime
+	}
+	if next.Total.IsZero() {
+		next.Total = prev.Total
+	}
+	if next.CouponCode == "" {
+		next.CouponCode = prev.CouponCode
+	}
+	return next
+}
+
+// 合并订单的上一个版本和新版本，返回合并后的订单，新版本是过期数据时返回 false
+//   - 都有修改时间时，修改时间更早的新版本是过期数据
+//   - 新版本没有修改时间（订单列表查询）而上一个版本有时，状态推进或退款金额增加的新版本整体覆盖，沿用上一个版本的修改时间；
+//     否则只用新版本的佣金、退款和风控信息更新订单回推和订单列表的版本，单订单查询的版本不更新
+//   - 订单回推沿用上一个版本的佣金、退款和风控信息，只有部分来源返回的字段新版本没有时沿用上一个版本
+func mergeOrder(prev *Order, next Order) (Order, bool) {
+	if prev == nil {
+		return next, true
+	}
+	if !prev.ModTime.IsZero() && next.ModTime.IsZero() {
+		if !isOrderAhead(*prev, next) {
+			if !next.hasSettlement() || prev.Source == OrderSourceQuery {
+				return Order{}, false
+			}
+			return applySettlement(*prev, next), true
+		}
+		next.ModTime = prev.ModTime
+	} else if !prev.ModTime.IsZero() && next.ModTime.Before(prev.ModTime) {
+		return Order{}, false
+	}
+	return fillMissing(*prev, mergeSettlement(prev, next)), true
+}
+
+// DetectOrderEvents 比较订单的上一个版本和最新版本，返回产生的生命周期事件
+// prev 为 nil 表示首次出现的订单；两个版本按修改时间、状态和退款金额合并，next 是过期数据时不产生事件
+// next 为订单回推时，事件的 After 沿用 prev 的佣金、退款和风控信息；prev 为订单回推时不比较这些信息
+// 需要跨越多次回推比较结算信息时使用 OrderTracker
+func DetectOrderEvents(prev *Order, next Order) []OrderEvent {
+	merged, ok := mergeOrder(prev, next)
+	if !ok {
+		return nil
+	}
+	var settled *Order
+	if prev != nil && prev.hasSettlement() {
+		settled = prev
+	}
+	return detectOrderEvents(prev, settled, merged)
+}
+
+// 比较订单版本，状态与 prev 比较，佣金、退款和风控信息与最近一个包含结算信息的版本 settled 比较
+func detectOrderEvents(prev *Order, settled *Order, next Order) []OrderEvent {
+	var before Order
+	if prev != nil {
+		before = *prev
+	}
+
+	var events []OrderEvent
+	emit := func(eventType OrderEventType) {
+		events = append(events, OrderEvent{Type: eventType, Before: before, After: next})
+	}
+
+	// 状态变化，状态9由包含风控信息的版本判断
+	if prev == nil || before.Status != next.Status {
+		switch next.Status {
+		case OrderStatusPaid:
+			emit(OrderPaid)
+		case OrderStatusCompleted:
+			emit(OrderCompleted)
+		}
+	}
+
+	// 订单回推不包含结算信息，不产生退款、风控和佣金事件
+	if !next.hasSettlement() {
+		return events
+	}
+	var last Order
+	if settled != nil {
+		last = *settled
+	}
+
+	// 状态9同时表示退款和风控，风控订单只产生风控事件
+	if next.Status == OrderStatusRefunded && !next.Risk && (settled == nil || last.Status != OrderStatusRefunded) {
+		emit(OrderRefunded)
+	}
+	// 未全部退款时退款金额增加
+	if next.Status != OrderStatusRefunded && next.RefundPrice.Cmp(last.RefundPrice) > 0 {
+		emit(OrderPartiallyRefunded)
+	}
+	if next.Risk && !last.Risk {
+		emit(OrderMarkedRisk)
+	}
+	if settled != nil && last.Commission() != next.Commission() {
+		emit(CommissionAdjusted)
+	}
+
+	return events
+}
+
+// OrderTracker 在内存中保存每个订单的最新版本，并在订单变化时产生生命周期事件
+// 订单回推沿用之前版本的佣金、退款和风控信息，这些信息与最近一个包含结算信息的版本比较
+type OrderTracker struct {
+	mu     sync.Mutex
+	orders map[string]trackedOrder
+}
+
+// 跟踪的订单
+type trackedOrder struct {
+	latest  Order  // 最新版本
+	settled *Order // 最近一个包含结算信息的版本，只收到过订单回推时为 nil
+}
+
+// NewOrderTracker 创建订单跟踪
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{orders: make(map[string]trackedOrder)}
+}
+
+// Track 记录订单的最新版本，返回与上一个版本相比产生的事件
+func (t *OrderTracker) Track(order Order) []OrderEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tracked, ok := t.orders[order.OrderID]
+	var prev *Order
+	if ok {
+		prev = &tracked.latest
+	}
+	order, ok = mergeOrder(prev, order)
+	if !ok {
+		return nil
+	}
+	events := detectOrderEvents(prev, tracked.settled, order)
+
+	tracked.latest = order
+	if order.hasSettlement() {
+		settled := order
+		tracked.settled = &settled
+	}
+	t.orders[order.OrderID] = tracked
+	return events
+}
+
+// Forget 删除订单的记录
+func (t *OrderTracker) Forget(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, orderID)
+}
@@ -0,0 +1,231 @@
+package meituan
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// 按来源创建测试订单
+func testOrder(source OrderSource, status OrderStatus, modTime int64) Order {
+	order := Order{OrderID: "1", Source: source, Status: status}
+	if modTime > 0 {
+		order.ModTime = time.Unix(modTime, 0)
+	}
+	return order
+}
+
+func TestOrderTrackerSequences(t *testing.T) {
+	query := func(status OrderStatus, modTime int64, f func(*Order)) Order {
+		order := testOrder(OrderSourceQuery, status, modTime)
+		order.Profit = 100
+		if f != nil {
+			f(&order)
+		}
+		return order
+	}
+	callback := func(status OrderStatus, modTime int64) Order {
+		return testOrder(OrderSourceCallback, status, modTime)
+	}
+	// 订单列表查询没有修改时间
+	list := func(status OrderStatus, f func(*Order)) Order {
+		return query(status, 0, func(o *Order) {
+			o.Source = OrderSourceList
+			if f != nil {
+				f(o)
+			}
+		})
+	}
+	risk := func(o *Order) { o.Risk = true }
+	partialRefund := func(o *Order) { o.RefundPrice, o.RefundProfit = 500, 20 }
+
+	tests := []struct {
+		name   string
+		orders []Order
+		want   [][]OrderEventType
+	}{
+		{
+			name:   "付款后完成",
+			orders: []Order{query(OrderStatusPaid, 1, nil), query(OrderStatusCompleted, 2, nil)},
+			want:   [][]OrderEventType{{OrderPaid}, {OrderCompleted}},
+		},
+		{
+			name:   "查询到全部退款",
+			orders: []Order{query(OrderStatusPaid, 1, nil), query(OrderStatusRefunded, 2, nil)},
+			want:   [][]OrderEventType{{OrderPaid}, {OrderRefunded}},
+		},
+		{
+			name:   "回推先到的风控订单",
+			orders: []Order{callback(OrderStatusRefunded, 1), query(OrderStatusRefunded, 1, risk)},
+			want:   [][]OrderEventType{nil, {OrderMarkedRisk}},
+		},
+		{
+			name:   "回推先到的退款订单",
+			orders: []Order{callback(OrderStatusRefunded, 1), query(OrderStatusRefunded, 1, nil)},
+			want:   [][]OrderEventType{nil, {OrderRefunded}},
+		},
+		{
+			name:   "回推先到的部分退款订单",
+			orders: []Order{callback(OrderStatusPaid, 1), query(OrderStatusPaid, 2, partialRefund)},
+			want:   [][]OrderEventType{{OrderPaid}, {OrderPartiallyRefunded}},
+		},
+		{
+			name:   "回推状态9后查询到非风控",
+			orders: []Order{query(OrderStatusPaid, 1, nil), callback(OrderStatusRefunded, 2), query(OrderStatusRefunded, 2, nil)},
+			want:   [][]OrderEventType{{OrderPaid}, nil, {OrderRefunded}},
+		},
+		{
+			name:   "回推状态9后查询到风控",
+			orders: []Order{query(OrderStatusPaid, 1, nil), callback(OrderStatusRefunded, 2), query(OrderStatusRefunded, 2, risk)},
+			want:   [][]OrderEventType{{OrderPaid}, nil, {OrderMarkedRisk}},
+		},
+		{
+			name:   "部分退款后收到回推不重复产生事件",
+			orders: []Order{query(OrderStatusPaid, 1, partialRefund), callback(OrderStatusPaid, 2), query(OrderStatusPaid, 3, partialRefund)},
+			want:   [][]OrderEventType{{OrderPaid, OrderPartiallyRefunded}, nil, nil},
+		},
+		{
+			name:   "全部退款后收到回推不重复产生事件",
+			orders: []Order{query(OrderStatusRefunded, 1, nil), callback(OrderStatusRefunded, 2), query(OrderStatusRefunded, 3, nil)},
+			want:   [][]OrderEventType{{OrderRefunded}, nil, nil},
+		},
+		{
+			name:   "风控订单收到回推后不重复标记",
+			orders: []Order{query(OrderStatusRefunded, 1, risk), callback(OrderStatusRefunded, 2), query(OrderStatusRefunded, 3, risk)},
+			want:   [][]OrderEventType{{OrderMarkedRisk}, nil, nil},
+		},
+		{
+			name: "回推后佣金调整",
+			orders: []Order{query(OrderStatusPaid, 1, nil), callback(OrderStatusCompleted, 2), query(OrderStatusCompleted, 3, func(o *Order) {
+				o.Profit = 80
+			})},
+			want: [][]OrderEventType{{OrderPaid}, {OrderCompleted}, {CommissionAdjusted}},
+		},
+		{
+			name:   "回推付款后订单列表全部退款",
+			orders: []Order{callback(OrderStatusPaid, 1), list(OrderStatusRefunded, nil)},
+			want:   [][]OrderEventType{{OrderPaid}, {OrderRefunded}},
+		},
+		{
+			name:   "回推付款后订单列表部分退款",
+			orders: []Order{callback(OrderStatusPaid, 1), list(OrderStatusPaid, partialRefund)},
+			want:   [][]OrderEventType{{OrderPaid}, {OrderPartiallyRefunded}},
+		},
+		{
+			name:   "回推状态9后订单列表风控",
+			orders: []Order{callback(OrderStatusRefunded, 1), list(OrderStatusRefunded, risk)},
+			want:   [][]OrderEventType{nil, {OrderMarkedRisk}},
+		},
+		{
+			name:   "回推完成后订单列表状态落后只更新结算信息",
+			orders: []Order{callback(OrderStatusCompleted, 2), list(OrderStatusPaid, nil), query(OrderStatusCompleted, 3, func(o *Order) { o.Profit = 80 })},
+			want:   [][]OrderEventType{{OrderCompleted}, nil, {CommissionAdjusted}},
+		},
+		{
+			name:   "查询后状态落后的订单列表不覆盖",
+			orders: []Order{query(OrderStatusCompleted, 2, nil), list(OrderStatusPaid, nil), query(OrderStatusCompleted, 3, nil)},
+			want:   [][]OrderEventType{{OrderCompleted}, nil, nil},
+		},
+		{
+			name:   "过期数据",
+			orders: []Order{query(OrderStatusCompleted, 2, nil), query(OrderStatusPaid, 1, nil)},
+			want:   [][]OrderEventType{{OrderCompleted}, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewOrderTracker()
+			for i, order := range tt.orders {
+				var got []OrderEventType
+				for _, event := range tracker.Track(order) {
+					got = append(got, event.Type)
+				}
+				if !reflect.DeepEqual(got, tt.want[i]) {
+					t.Errorf("第%d个版本 %s 事件 = %v, want %v", i+1, order.Source, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOrderTrackerMergesCallbackSettlement(t *testing.T) {
+	tracker := NewOrderTracker()
+	first := testOrder(OrderSourceQuery, OrderStatusPaid, 1)
+	first.Risk = true
+	first.Profit, first.RefundPrice, first.RefundProfit = 300, 500, 20
+	first.RefundTime = time.Unix(1, 0)
+	tracker.Track(first)
+
+	events := tracker.Track(testOrder(OrderSourceCallback, OrderStatusCompleted, 2))
+	if len(events) != 1 || events[0].Type != OrderCompleted {
+		t.Fatalf("事件 %v", events)
+	}
+	after := events[0].After
+	if after.Source != OrderSourceCallback || !after.Risk || after.Profit != 300 || after.RefundPrice != 500 || after.RefundProfit != 20 || !after.RefundTime.Equal(first.RefundTime) {
+		t.Errorf("合并后的订单 %+v", after)
+	}
+	if after.Commission() != 280 {
+		t.Errorf("Commission = %s", after.Commission())
+	}
+}
+
+func TestDetectOrderEvents(t *testing.T) {
+	prev := testOrder(OrderSourceQuery, OrderStatusPaid, 1)
+	prev.Profit = 100
+
+	// 订单回推沿用上一个版本的结算信息，不产生佣金调整
+	events := DetectOrderEvents(&prev, testOrder(OrderSourceCallback, OrderStatusRefunded, 2))
+	if len(events) != 0 {
+		t.Errorf("回推状态9不应产生事件: %v", events)
+	}
+	events = DetectOrderEvents(&prev, testOrder(OrderSourceCallback, OrderStatusCompleted, 2))
+	if len(events) != 1 || events[0].Type != OrderCompleted || events[0].After.Profit != 100 {
+		t.Errorf("事件 %+v", events)
+	}
+
+	// 没有修改时间但状态推进的订单列表版本
+	list := testOrder(OrderSourceList, OrderStatusRefunded, 0)
+	list.Profit = prev.Profit
+	events = DetectOrderEvents(&prev, list)
+	if len(events) != 1 || events[0].Type != OrderRefunded || !events[0].After.ModTime.Equal(prev.ModTime) {
+		t.Errorf("事件 %+v", events)
+	}
+
+	// 首次出现的订单
+	events = DetectOrderEvents(nil, prev)
+	if len(events) != 1 || events[0].Type != OrderPaid {
+		t.Errorf("事件 %+v", events)
+	}
+}
+
+func TestMergeOrder(t *testing.T) {
+	callback := testOrder(OrderSourceCallback, OrderStatusPaid, 2)
+	callback.Uid, callback.Type, callback.Total, callback.Quantity = "u1", "4", 3000, 2
+	callback.OrderTime = time.Unix(1, 0)
+
+	// 状态推进的订单列表版本整体覆盖，沿用回推的修改时间和只有回推返回的字段
+	list := testOrder(OrderSourceList, OrderStatusRefunded, 0)
+	list.Profit, list.RefundPrice = 100, 3000
+	merged, ok := mergeOrder(&callback, list)
+	want := list
+	want.ModTime, want.Uid, want.Type, want.Total, want.Quantity, want.OrderTime = callback.ModTime, "u1", "4", 3000, 2, callback.OrderTime
+	if !ok || !reflect.DeepEqual(merged, want) {
+		t.Errorf("合并后的订单 %+v, %v", merged, ok)
+	}
+
+	// 状态落后的订单列表版本只更新结算信息
+	completed := callback
+	completed.Status = OrderStatusCompleted
+	list.Status, list.RefundPrice = OrderStatusPaid, 0
+	merged, ok = mergeOrder(&completed, list)
+	want = completed
+	want.Source, want.Profit = OrderSourceList, 100
+	if !ok || !reflect.DeepEqual(merged, want) {
+		t.Errorf("合并后的订单 %+v, %v", merged, ok)
+	}
+
+	// 没有修改时间且状态落后的订单回推是过期数据
+	if _, ok = mergeOrder(&completed, testOrder(OrderSourceCallback, OrderStatusPaid, 0)); ok {
+		t.Error("状态落后的订单回推不应合并")
+	}
+}
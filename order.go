@@ -0,0 +1,274 @@
+package meituan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderStatus 订单状态
+type OrderStatus int
+
+const (
+	OrderStatusPaid      OrderStatus = 1 // 已付款
+	OrderStatusCompleted OrderStatus = 8 // 已完成
+	OrderStatusRefunded  OrderStatus = 9 // 已退款或风控
+)
+
+// TradeType 订单奖励类型
+type TradeType int
+
+const (
+	TradeTypeCps       TradeType = 2 // cps
+	TradeTypeFirstBuy  TradeType = 3 // 首购奖励
+	TradeTypeRetention TradeType = 5 // 留存奖励
+)
+
+// 时间字符串的时区
+var orderLocation = time.FixedZone("CST", 8*3600)
+
+// Order 订单，订单列表查询、单订单查询和订单回推统一转换为该结构
+type Order struct {
+	OrderID         string       // 订单id
+	Source          OrderSource  // 订单来源
+	AppKey          string       // 媒体名称
+	Sid             string       // 推广位sid
+	Uid             string       // 渠道id，订单回推会返回该字段
+	ActID           int          // 活动id
+	BusinessLine    BusinessLine // 业务线
+	SubBusinessLine int          // 子业务线
+	Type            string       // 订单类型，订单回推会返回该字段
+	Title           string       // 订单标题
+	ProductID       string       // 商品ID
+	ProductName     string       // 商品名称
+	Quantity        int          // 商品数量
+	Status          OrderStatus  // 订单状态
+	Risk            bool         // 是否风控订单
+	TradeTypes      []TradeType  // 订单的奖励类型
+	OrderTime       time.Time    // 下单时间，订单回推会返回该字段
+	PayTime         time.Time    // 订单支付时间
+	ModTime         time.Time    // 订单信息修改时间
+	RefundTime      time.Time    // 最近一次退款时间
+	Total           Money        // 订单总金额，订单回推会返回该字段
+	PayPrice        Money        // 订单用户实际支付金额
+	Profit          Money        // 订单预估返佣金额
+	CpaProfit       Money        // 订单预估cpa总收益
+	RefundPrice     Money        // 订单实际退款金额
+	RefundProfit    Money        // 订单需要扣除的返佣金额
+	CpaRefundProfit Money        // 订单需要扣除的cpa返佣金额
+	ConsumeProfit   Money        // 已核销的佣金金额，部分核销的订单为核销佣金列表之和
+	CouponCode      string       // 券码
+}
+
+// 订单字段转换，记录第一个错误
+type orderParser struct {
+	err error
+}
+
+func (p *orderParser) fail(field string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("订单字段 %s 转换失败: %w", field, err)
+	}
+}
+
+// 解析金额，单位元
+func (p *orderParser) parseMoney(field string, s string) Money {
+	m, err := ParseYuan(s)
+	if err != nil {
+		p.fail(field, err)
+	}
+	return m
+}
+
+// 解析时间，支持10位时间戳和 2006-01-02 15:04:05 格式
+func (p *orderParser) parseTime(field string, s string) time.Time {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return time.Time{}
+	}
+	if ts, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(ts, 0)
+	}
+	t, err := time.ParseInLocation(time.DateTime, s, orderLocation)
+	if err != nil {
+		p.fail(field, err)
+	}
+	return t
+}
+
+// 解析整数
+func (p *orderParser) parseInt(field string, s string) int {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		p.fail(field, err)
+	}
+	return i
+}
+
+// 解析奖励类型，支持 [2,3] 和 2,3 格式
+func (p *orderParser) parseTradeTypes(field string, s string) []TradeType {
+	s = strings.Trim(strings.TrimSpace(s), "[]")
+	if s == "" {
+		return nil
+	}
+	var tradeTypes []TradeType
+	for _, v := range strings.Split(s, ",") {
+		tradeTypes = append(tradeTypes, TradeType(p.parseInt(field, v)))
+	}
+	return tradeTypes
+}
+
+// 汇总退款列表，返回退款金额之和与最近一次退款时间
+func (p *orderParser) sumRefunds(list []ApiOrderRefundInfo) (price Money, latest time.Time) {
+	for i, refund := range list {
+		price = price.Add(p.parseMoney(fmt.Sprintf("refundInfoList[%d].refundPrice", i), refund.RefundPrice))
+		if t := p.parseTime(fmt.Sprintf("refundInfoList[%d].refundTime", i), refund.RefundTime); t.After(latest) {
+			latest = t
+		}
+	}
+	return price, latest
+}
+
+// 汇总退款佣金列表
+func (p *orderParser) sumRefundProfits(list []ApiOrderRefundProfit) (profit Money) {
+	for i, refund := range list {
+		profit = profit.Add(p.parseMoney(fmt.Sprintf("refundProfitList[%d].refundProfit", i), refund.RefundProfit))
+	}
+	return profit
+}
+
+// 汇总核销佣金列表
+func (p *orderParser) sumConsumeProfits(list []ApiOrderConsumeProfit) (profit Money) {
+	for i, consume := range list {
+		profit = profit.Add(p.parseMoney(fmt.Sprintf("consumeProfitList[%d].consumeProfit", i), consume.ConsumeProfit))
+	}
+	return profit
+}
+
+func toTradeTypes(list []int) []TradeType {
+	if len(list) == 0 {
+		return nil
+	}
+	tradeTypes := make([]TradeType, len(list))
+	for i, v := range list {
+		tradeTypes[i] = TradeType(v)
+	}
+	return tradeTypes
+}
+
+// Order 转换为统一的订单
+// 商品数量、券码和核销佣金来自扩展信息，退款金额、退款时间和退款佣金为空时使用扩展信息中的退款列表
+func (o ApiOrderListOrder) Order() (Order, error) {
+	var p orderParser
+	extra, err := o.ExtraInfo()
+	if err != nil {
+		p.fail("extra", err)
+	}
+	order := Order{
+		OrderID:         o.Orderid,
+		Source:          OrderSourceList,
+		AppKey:          o.Appkey,
+		Sid:             o.Sid,
+		ActID:           o.ActId,
+		BusinessLine:    BusinessLine(o.BusinessLine),
+		SubBusinessLine: o.SubBusinessLine,
+		Title:           o.Smstitle,
+		ProductID:       o.ProductId,
+		ProductName:     o.ProductName,
+		Status:          OrderStatus(o.Status),
+		Risk:            o.RiskOrder == 1,
+		TradeTypes:      toTradeTypes(o.TradeTypeList),
+		Quantity:        extra.Quantity,
+		PayTime:         p.parseTime("paytime", o.Paytime),
+		ModTime:         p.parseTime("modTime", o.ModTime),
+		RefundTime:      p.parseTime("refundtime", o.Refundtime),
+		PayPrice:        p.parseMoney("payprice", o.Payprice),
+		Profit:          p.parseMoney("profit", o.Profit),
+		CpaProfit:       p.parseMoney("cpaProfit", o.CpaProfit),
+		RefundPrice:     p.parseMoney("refundprice", o.Refundprice),
+		RefundProfit:    p.parseMoney("refundprofit", o.Refundprofit),
+		CpaRefundProfit: p.parseMoney("cpaRefundProfit", o.CpaRefundProfit),
+		ConsumeProfit:   p.sumConsumeProfits(extra.ConsumeProfitList),
+		CouponCode:      extra.CouponCode,
+	}
+	refundPrice, refundTime := p.sumRefunds(extra.RefundInfoList)
+	if o.Refundprice == "" {
+		order.RefundPrice = refundPrice
+	}
+	if o.Refundtime == "" {
+		order.RefundTime = refundTime
+	}
+	if o.Refundprofit == "" {
+		order.RefundProfit = p.sumRefundProfits(extra.RefundProfitList)
+	}
+	return order, p.err
+}
+
+// Order 转换为统一的订单
+func (r ApiOrderResponse) Order() (Order, error) {
+	var p orderParser
+	d := r.Data
+	order := Order{
+		OrderID:         d.OrderId,
+		Source:          OrderSourceQuery,
+		AppKey:          d.Appkey,
+		Sid:             d.Sid,
+		ActID:           d.ActId,
+		BusinessLine:    BusinessLine(d.BusinessLine),
+		SubBusinessLine: d.SubBusinessLine,
+		Title:           d.Smstitle,
+		ProductID:       d.ProductId,
+		ProductName:     d.ProductName,
+		Quantity:        d.Quantity,
+		Status:          OrderStatus(d.Status),
+		Risk:            d.RiskApiOrder == 1,
+		TradeTypes:      toTradeTypes(d.TradeTypeList),
+		PayTime:         p.parseTime("paytime", d.Paytime),
+		ModTime:         p.parseTime("modTime", d.ModTime),
+		PayPrice:        p.parseMoney("payprice", d.Payprice),
+		Profit:          p.parseMoney("profit", d.Profit),
+		CpaProfit:       p.parseMoney("cpaProfit", d.CpaProfit),
+		RefundProfit:    p.parseMoney("refundprofit", d.Refundprofit),
+		CpaRefundProfit: p.parseMoney("cpaRefundProfit", d.CpaRefundProfit),
+		ConsumeProfit:   p.sumConsumeProfits(d.ConsumeProfitList),
+		CouponCode:      d.CouponCode,
+	}
+	order.RefundPrice, order.RefundTime = p.sumRefunds(d.RefundInfoList)
+	if d.Refundprofit == "" {
+		order.RefundProfit = p.sumRefundProfits(d.RefundProfitList)
+	}
+	return order, p.err
+}
+
+// Order 转换为统一的订单
+func (r ServeHttpOrderHttpRequest) Order() (Order, error) {
+	var p orderParser
+	order := Order{
+		OrderID:         r.Orderid,
+		Source:          OrderSourceCallback,
+		AppKey:          r.Appkey,
+		Sid:             r.Sid,
+		Uid:             r.Uid,
+		ActID:           p.parseInt("actId", r.ActId),
+		BusinessLine:    BusinessLine(p.parseInt("businessLine", r.BusinessLine)),
+		SubBusinessLine: p.parseInt("subBusinessLine", r.SubBusinessLine),
+		Type:            r.Type,
+		Title:           r.Smstitle,
+		ProductID:       r.ProductId,
+		ProductName:     r.ProductName,
+		Quantity:        p.parseInt("quantity", r.Quantity),
+		Status:          OrderStatus(p.parseInt("status", r.Status)),
+		TradeTypes:      p.parseTradeTypes("tradeTypeList", r.TradeTypeList),
+		OrderTime:       p.parseTime("ordertime", r.Ordertime),
+		PayTime:         p.parseTime("paytime", r.Paytime),
+		ModTime:         p.parseTime("modTime", r.ModTime),
+		Total:           p.parseMoney("total", r.Total),
+		PayPrice:        p.parseMoney("payPrice", r.PayPrice),
+	}
+	return order, p.err
+}
@@ -0,0 +1,115 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOrderNotFound 订单不存在
+var ErrOrderNotFound = errors.New("订单不存在")
+
+// OrderQuery 订单查询条件，零值的条件不限制
+type OrderQuery struct {
+	Sid       string      // 推广位sid
+	StartTime time.Time   // 支付时间起始，包含
+	EndTime   time.Time   // 支付时间截止，不包含
+	Status    OrderStatus // 订单状态
+	Limit     int         // 返回数量，为0时不限制
+	Offset    int         // 跳过数量
+}
+
+// 订单是否满足查询条件
+func (q OrderQuery) match(order Order) bool {
+	if q.Sid != "" && order.Sid != q.Sid {
+		return false
+	}
+	if !q.StartTime.IsZero() && order.PayTime.Before(q.StartTime) {
+		return false
+	}
+	if !q.EndTime.IsZero() && !order.PayTime.Before(q.EndTime) {
+		return false
+	}
+	if q.Status != 0 && order.Status != q.Status {
+		return false
+	}
+	return true
+}
+
+// OrderStore 订单存储
+type OrderStore interface {
+	// Upsert 保存订单，返回是否写入，与已保存的版本按修改时间、状态和退款金额合并：
+	// 修改时间更早的版本不写入；没有修改时间的订单列表版本状态推进或退款金额增加时覆盖并保留已保存的修改时间，
+	// 否则只更新订单回推和订单列表版本的佣金、退款和风控信息；订单回推不覆盖已保存的佣金、退款和风控信息
+	Upsert(ctx context.Context, order Order) (bool, error)
+	// Get 获取订单，不存在时返回 ErrOrderNotFound
+	Get(ctx context.Context, orderID string) (Order, error)
+	// Query 按条件查询订单，按支付时间和订单id排序
+	Query(ctx context.Context, query OrderQuery) ([]Order, error)
+}
+
+// MemoryOrderStore 内存订单存储，进程重启后丢失
+type MemoryOrderStore struct {
+	mu     sync.RWMutex
+	orders map[string]Order
+}
+
+// NewMemoryOrderStore 创建内存订单存储
+func NewMemoryOrderStore() *MemoryOrderStore {
+	return &MemoryOrderStore{orders: make(map[string]Order)}
+}
+
+func (s *MemoryOrderStore) Upsert(ctx context.Context, order Order) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var prev *Order
+	if saved, ok := s.orders[order.OrderID]; ok {
+		prev = &saved
+	}
+	order, ok := mergeOrder(prev, order)
+	if !ok {
+		return false, nil
+	}
+	s.orders[order.OrderID] = order
+	return true, nil
+}
+
+func (s *MemoryOrderStore) Get(ctx context.Context, orderID string) (Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	order, ok := s.orders[orderID]
+	if !ok {
+		return Order{}, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *MemoryOrderStore) Query(ctx context.Context, query OrderQuery) ([]Order, error) {
+	s.mu.RLock()
+	var orders []Order
+	for _, order := range s.orders {
+		if query.match(order) {
+			orders = append(orders, order)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(orders, func(i, j int) bool {
+		if !orders[i].PayTime.Equal(orders[j].PayTime) {
+			return orders[i].PayTime.Before(orders[j].PayTime)
+		}
+		return orders[i].OrderID < orders[j].OrderID
+	})
+	if query.Offset > 0 {
+		if query.Offset >= len(orders) {
+			return nil, nil
+		}
+		orders = orders[query.Offset:]
+	}
+	if query.Limit > 0 && query.Limit < len(orders) {
+		orders = orders[:query.Limit]
+	}
+	return orders, nil
+}
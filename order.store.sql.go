@@ -0,0 +1,244 @@
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 订单表
+const orderTable = LogTable + "_order"
+
+// 订单表字段，顺序与 orderRow 一致
+var orderColumns = []string{
+	"order_id", "source", "app_key", "sid", "uid", "act_id", "business_line", "sub_business_line",
+	"type", "title", "product_id", "product_name", "quantity", "status", "risk", "trade_types",
+	"order_time", "pay_time", "mod_time", "refund_time",
+	"total", "pay_price", "profit", "cpa_profit", "refund_price", "refund_profit", "cpa_refund_profit", "consume_profit",
+	"coupon_code", "updated_at",
+}
+
+// SQLOrderStore 基于 database/sql 的订单存储，支持 SQLite 和 PostgreSQL
+// 使用前需要调用 MigrateSQL 创建数据表，驱动由调用方引入
+type SQLOrderStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLOrderStore 创建数据库订单存储
+func NewSQLOrderStore(db *sql.DB, dialect SQLDialect) (*SQLOrderStore, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	return &SQLOrderStore{db: db, dialect: dialect}, nil
+}
+
+// 订单转换为字段值
+func orderRow(order Order) []any {
+	return []any{
+		order.OrderID, string(order.Source), order.AppKey, order.Sid, order.Uid, order.ActID, int(order.BusinessLine), order.SubBusinessLine,
+		order.Type, order.Title, order.ProductID, order.ProductName, order.Quantity, int(order.Status), boolToInt(order.Risk), joinTradeTypes(order.TradeTypes),
+		unixOrZero(order.OrderTime), unixOrZero(order.PayTime), unixOrZero(order.ModTime), unixOrZero(order.RefundTime),
+		order.Total.Fen(), order.PayPrice.Fen(), order.Profit.Fen(), order.CpaProfit.Fen(), order.RefundPrice.Fen(), order.RefundProfit.Fen(), order.CpaRefundProfit.Fen(), order.ConsumeProfit.Fen(),
+		order.CouponCode, time.Now().Unix(),
+	}
+}
+
+// Upsert 保存订单，在事务中读取已保存的版本并按 OrderStore 的规则合并
+// PostgreSQL 使用 SELECT ... FOR UPDATE 锁定已保存的订单，SQLite 的写事务本身互斥
+func (s *SQLOrderStore) Upsert(ctx context.Context, order Order) (bool, error) {
+	written, conflict, err := s.upsert(ctx, order)
+	if conflict {
+		// 并发插入同一个新订单时，插入失败的一方重新读取后合并
+		written, _, err = s.upsert(ctx, order)
+	}
+	return written, err
+}
+
+// 在事务中保存订单，订单不存在但插入时已被并发插入返回 conflict
+func (s *SQLOrderStore) upsert(ctx context.Context, order Order) (written bool, conflict bool, err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, false, err
+	}
+	defer tx.Rollback() // nolint
+
+	statement := "SELECT " + strings.Join(orderColumns, ", ") + " FROM " + orderTable + " WHERE order_id = ?"
+	if s.dialect == SQLDialectPostgres {
+		statement += " FOR UPDATE"
+	}
+	rows, err := tx.QueryContext(ctx, s.dialect.rebind(statement), order.OrderID)
+	if err != nil {
+		return false, false, err
+	}
+	saved, err := scanOrders(rows)
+	if err != nil {
+		return false, false, err
+	}
+
+	if len(saved) == 0 {
+		statement = "INSERT INTO " + orderTable + " (" + strings.Join(orderColumns, ", ") + ") VALUES (" +
+			strings.TrimSuffix(strings.Repeat("?, ", len(orderColumns)), ", ") + ") ON CONFLICT (order_id) DO NOTHING"
+		result, err := tx.ExecContext(ctx, s.dialect.rebind(statement), orderRow(order)...)
+		if err != nil {
+			return false, false, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return false, false, err
+		}
+		if affected == 0 {
+			return false, true, nil
+		}
+		return true, false, tx.Commit()
+	}
+
+	merged, ok := mergeOrder(&saved[0], order)
+	if !ok {
+		return false, false, nil
+	}
+	set := make([]string, 0, len(orderColumns)-1)
+	for _, column := range orderColumns[1:] {
+		set = append(set, column+" = ?")
+	}
+	row := orderRow(merged)
+	if _, err = tx.ExecContext(ctx, s.dialect.rebind("UPDATE "+orderTable+" SET "+strings.Join(set, ", ")+" WHERE order_id = ?"), append(row[1:], row[0])...); err != nil {
+		return false, false, err
+	}
+	return true, false, tx.Commit()
+}
+
+// Get 获取订单
+func (s *SQLOrderStore) Get(ctx context.Context, orderID string) (Order, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind("SELECT "+strings.Join(orderColumns, ", ")+" FROM "+orderTable+" WHERE order_id = ?"), orderID)
+	if err != nil {
+		return Order{}, err
+	}
+	orders, err := scanOrders(rows)
+	if err != nil {
+		return Order{}, err
+	}
+	if len(orders) == 0 {
+		return Order{}, ErrOrderNotFound
+	}
+	return orders[0], nil
+}
+
+// Query 按条件查询订单
+func (s *SQLOrderStore) Query(ctx context.Context, query OrderQuery) ([]Order, error) {
+	var (
+		where []string
+		args  []any
+	)
+	if query.Sid != "" {
+		where = append(where, "sid = ?")
+		args = append(args, query.Sid)
+	}
+	if !query.StartTime.IsZero() {
+		where = append(where, "pay_time >= ?")
+		args = append(args, query.StartTime.Unix())
+	}
+	if !query.EndTime.IsZero() {
+		where = append(where, "pay_time < ?")
+		args = append(args, query.EndTime.Unix())
+	}
+	if query.Status != 0 {
+		where = append(where, "status = ?")
+		args = append(args, int(query.Status))
+	}
+
+	statement := "SELECT " + strings.Join(orderColumns, ", ") + " FROM " + orderTable
+	if len(where) > 0 {
+		statement += " WHERE " + strings.Join(where, " AND ")
+	}
+	statement += " ORDER BY pay_time, order_id"
+	if query.Limit > 0 {
+		statement += " LIMIT " + strconv.Itoa(query.Limit)
+	} else if query.Offset > 0 && s.dialect == SQLDialectSQLite {
+		// SQLite 的 OFFSET 必须跟在 LIMIT 之后
+		statement += " LIMIT -1"
+	}
+	if query.Offset > 0 {
+		statement += " OFFSET " + strconv.Itoa(query.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	return scanOrders(rows)
+}
+
+// 读取订单并关闭结果集
+func scanOrders(rows *sql.Rows) ([]Order, error) {
+	defer rows.Close()
+
+	var orders []Order
+	for rows.Next() {
+		var (
+			order                                                                    Order
+			source, tradeTypes                                                       string
+			businessLine, status, risk                                               int
+			orderTime, payTime, modTime, refundTime, updatedAt                       int64
+			total, payPrice, profit, cpaProfit, refundPrice, refundProfit, cpaRefund int64
+			consumeProfit                                                            int64
+		)
+		err := rows.Scan(
+			&order.OrderID, &source, &order.AppKey, &order.Sid, &order.Uid, &order.ActID, &businessLine, &order.SubBusinessLine,
+			&order.Type, &order.Title, &order.ProductID, &order.ProductName, &order.Quantity, &status, &risk, &tradeTypes,
+			&orderTime, &payTime, &modTime, &refundTime,
+			&total, &payPrice, &profit, &cpaProfit, &refundPrice, &refundProfit, &cpaRefund, &consumeProfit,
+			&order.CouponCode, &updatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var p orderParser
+		order.Source = OrderSource(source)
+		order.BusinessLine = BusinessLine(businessLine)
+		order.Status = OrderStatus(status)
+		order.Risk = risk != 0
+		order.TradeTypes = p.parseTradeTypes("trade_types", tradeTypes)
+		order.OrderTime = timeOrZero(orderTime)
+		order.PayTime = timeOrZero(payTime)
+		order.ModTime = timeOrZero(modTime)
+		order.RefundTime = timeOrZero(refundTime)
+		order.Total = Money(total)
+		order.PayPrice = Money(payPrice)
+		order.Profit = Money(profit)
+		order.CpaProfit = Money(cpaProfit)
+		order.RefundPrice = Money(refundPrice)
+		order.RefundProfit = Money(refundProfit)
+		order.CpaRefundProfit = Money(cpaRefund)
+		order.ConsumeProfit = Money(consumeProfit)
+		if p.err != nil {
+			return nil, p.err
+		}
+		orders = append(orders, order)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func joinTradeTypes(tradeTypes []TradeType) string {
+	s := make([]string, len(tradeTypes))
+	for i, v := range tradeTypes {
+		s[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(s, ",")
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+var _ OrderStore = (*SQLOrderStore)(nil)
+var _ OrderStore = (*MemoryOrderStore)(nil)
@@ -0,0 +1,246 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 测试使用的订单存储
+func testOrderStores(t *testing.T) map[string]OrderStore {
+	t.Helper()
+	stores := map[string]OrderStore{"memory": NewMemoryOrderStore()}
+	if newTestDB == nil {
+		return stores
+	}
+	store, err := NewSQLOrderStore(openTestDB(t), SQLDialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores["sql"] = store
+	return stores
+}
+
+func TestOrderStoreUpsert(t *testing.T) {
+	query := Order{
+		OrderID:         "1",
+		Source:          OrderSourceQuery,
+		Sid:             "sid1",
+		Status:          OrderStatusPaid,
+		Risk:            true,
+		TradeTypes:      []TradeType{TradeTypeCps, TradeTypeFirstBuy},
+		PayTime:         time.Unix(1700000000, 0),
+		ModTime:         time.Unix(1700000100, 0),
+		RefundTime:      time.Unix(1700000050, 0),
+		PayPrice:        2580,
+		Profit:          129,
+		CpaProfit:       10,
+		RefundPrice:     500,
+		RefundProfit:    20,
+		CpaRefundProfit: 1,
+		ConsumeProfit:   60,
+		CouponCode:      "C1",
+	}
+
+	for name, store := range testOrderStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			if _, err := store.Get(ctx, "1"); !errors.Is(err, ErrOrderNotFound) {
+				t.Fatalf("Get 不存在的订单: %v", err)
+			}
+
+			written, err := store.Upsert(ctx, query)
+			if err != nil || !written {
+				t.Fatalf("Upsert = %v, %v", written, err)
+			}
+			got, err := store.Get(ctx, "1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, query) {
+				t.Errorf("Get = %+v, want %+v", got, query)
+			}
+
+			// 没有修改时间且状态和退款没有推进的订单列表版本不覆盖单订单查询的版本
+			list := query
+			list.Source, list.ModTime, list.Profit = OrderSourceList, time.Time{}, 1
+			if written, err = store.Upsert(ctx, list); err != nil || written {
+				t.Errorf("订单列表版本 Upsert = %v, %v", written, err)
+			}
+
+			// 状态推进的订单列表版本更新状态和退款信息，保留已保存的修改时间
+			list.Status, list.RefundPrice, list.RefundProfit = OrderStatusCompleted, 800, 30
+			if written, err = store.Upsert(ctx, list); err != nil || !written {
+				t.Fatalf("订单列表版本 Upsert = %v, %v", written, err)
+			}
+			want := list
+			want.ModTime = query.ModTime
+			if got, err = store.Get(ctx, "1"); err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("订单列表版本 Get = %+v, want %+v, %v", got, want, err)
+			}
+
+			// 修改时间更早的版本不覆盖
+			stale := query
+			stale.ModTime, stale.Status = query.ModTime.Add(-time.Second), OrderStatusCompleted
+			if written, err = store.Upsert(ctx, stale); err != nil || written {
+				t.Errorf("过期版本 Upsert = %v, %v", written, err)
+			}
+
+			// 订单回推保留已保存的结算字段
+			callback := Order{
+				OrderID:  "1",
+				Source:   OrderSourceCallback,
+				Sid:      "sid1",
+				Uid:      "u1",
+				Status:   OrderStatusCompleted,
+				PayTime:  query.PayTime,
+				ModTime:  query.ModTime.Add(time.Second),
+				Total:    3000,
+				PayPrice: 2580,
+			}
+			if written, err = store.Upsert(ctx, callback); err != nil || !written {
+				t.Fatalf("订单回推 Upsert = %v, %v", written, err)
+			}
+			if got, err = store.Get(ctx, "1"); err != nil {
+				t.Fatal(err)
+			}
+			want = callback
+			want.Risk, want.RefundTime = list.Risk, list.RefundTime
+			want.Profit, want.CpaProfit = list.Profit, list.CpaProfit
+			want.RefundPrice, want.RefundProfit, want.CpaRefundProfit = list.RefundPrice, list.RefundProfit, list.CpaRefundProfit
+			want.ConsumeProfit, want.CouponCode = list.ConsumeProfit, list.CouponCode
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("订单回推后 Get = %+v, want %+v", got, want)
+			}
+
+			// 包含结算信息的新版本覆盖结算字段
+			next := query
+			next.ModTime, next.Risk, next.Profit = callback.ModTime.Add(time.Second), false, 100
+			if written, err = store.Upsert(ctx, next); err != nil || !written {
+				t.Fatalf("新版本 Upsert = %v, %v", written, err)
+			}
+			if got, err = store.Get(ctx, "1"); err != nil || got.Risk || got.Profit != 100 || got.Source != OrderSourceQuery {
+				t.Errorf("新版本 Get = %+v, %v", got, err)
+			}
+
+			// 没有修改时间的版本可以互相覆盖
+			first := Order{OrderID: "2", Source: OrderSourceList, Status: OrderStatusPaid}
+			second := Order{OrderID: "2", Source: OrderSourceList, Status: OrderStatusCompleted}
+			for _, order := range []Order{first, second} {
+				if written, err = store.Upsert(ctx, order); err != nil || !written {
+					t.Fatalf("订单列表版本 Upsert = %v, %v", written, err)
+				}
+			}
+			if got, err = store.Get(ctx, "2"); err != nil || got.Status != OrderStatusCompleted {
+				t.Errorf("Get = %+v, %v", got, err)
+			}
+
+			// 订单回推付款后，订单列表查询到退款
+			paid := Order{OrderID: "3", Source: OrderSourceCallback, Uid: "u1", Status: OrderStatusPaid, ModTime: query.ModTime, Total: 3000}
+			refunded := Order{OrderID: "3", Source: OrderSourceList, Status: OrderStatusRefunded, Profit: 129, RefundPrice: 2580, RefundProfit: 129}
+			for _, order := range []Order{paid, refunded} {
+				if written, err = store.Upsert(ctx, order); err != nil || !written {
+					t.Fatalf("%s Upsert = %v, %v", order.Source, written, err)
+				}
+			}
+			want = refunded
+			want.Uid, want.ModTime, want.Total = paid.Uid, paid.ModTime, paid.Total
+			if got, err = store.Get(ctx, "3"); err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("退款后 Get = %+v, want %+v, %v", got, want, err)
+			}
+
+			// 订单回推完成后，状态落后的订单列表版本只更新结算信息
+			completed := Order{OrderID: "4", Source: OrderSourceCallback, Status: OrderStatusCompleted, ModTime: query.ModTime}
+			behind := Order{OrderID: "4", Source: OrderSourceList, Status: OrderStatusPaid, Profit: 129}
+			for _, order := range []Order{completed, behind} {
+				if written, err = store.Upsert(ctx, order); err != nil || !written {
+					t.Fatalf("%s Upsert = %v, %v", order.Source, written, err)
+				}
+			}
+			want = completed
+			want.Source, want.Profit = OrderSourceList, 129
+			if got, err = store.Get(ctx, "4"); err != nil || !reflect.DeepEqual(got, want) {
+				t.Errorf("结算信息更新后 Get = %+v, want %+v, %v", got, want, err)
+			}
+		})
+	}
+}
+
+// 需要配合 go test -race 运行
+func TestOrderStoreConcurrentUpsert(t *testing.T) {
+	for name, store := range testOrderStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			var wg sync.WaitGroup
+			for i := 1; i <= 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					order := Order{OrderID: "1", Source: OrderSourceQuery, Status: OrderStatusPaid, ModTime: time.Unix(int64(i), 0), Profit: Money(i)}
+					if _, err := store.Upsert(ctx, order); err != nil {
+						t.Error(err)
+					}
+				}(i)
+			}
+			wg.Wait()
+
+			// 无论写入顺序，保存的都是修改时间最晚的版本
+			got, err := store.Get(ctx, "1")
+			if err != nil || got.ModTime.Unix() != 8 || got.Profit != 8 {
+				t.Errorf("Get = %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestOrderStoreQuery(t *testing.T) {
+	base := time.Unix(1700000000, 0)
+	orders := []Order{
+		{OrderID: "a", Source: OrderSourceList, Sid: "s1", Status: OrderStatusPaid, PayTime: base},
+		{OrderID: "b", Source: OrderSourceList, Sid: "s1", Status: OrderStatusCompleted, PayTime: base.Add(time.Hour)},
+		{OrderID: "c", Source: OrderSourceList, Sid: "s2", Status: OrderStatusPaid, PayTime: base.Add(time.Hour)},
+		{OrderID: "d", Source: OrderSourceList, Sid: "s1", Status: OrderStatusPaid, PayTime: base.Add(2 * time.Hour)},
+	}
+	tests := []struct {
+		name  string
+		query OrderQuery
+		want  []string
+	}{
+		{name: "全部", want: []string{"a", "b", "c", "d"}},
+		{name: "推广位", query: OrderQuery{Sid: "s1"}, want: []string{"a", "b", "d"}},
+		{name: "支付时间", query: OrderQuery{StartTime: base.Add(time.Hour), EndTime: base.Add(2 * time.Hour)}, want: []string{"b", "c"}},
+		{name: "状态", query: OrderQuery{Status: OrderStatusPaid}, want: []string{"a", "c", "d"}},
+		{name: "数量", query: OrderQuery{Limit: 2}, want: []string{"a", "b"}},
+		{name: "数量和跳过", query: OrderQuery{Limit: 2, Offset: 1}, want: []string{"b", "c"}},
+		{name: "只跳过", query: OrderQuery{Offset: 2}, want: []string{"c", "d"}},
+		{name: "组合条件", query: OrderQuery{Sid: "s1", Status: OrderStatusPaid, Offset: 1}, want: []string{"d"}},
+		{name: "没有结果", query: OrderQuery{Sid: "s3"}},
+	}
+
+	for name, store := range testOrderStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, order := range orders {
+				if _, err := store.Upsert(ctx, order); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, tt := range tests {
+				result, err := store.Query(ctx, tt.query)
+				if err != nil {
+					t.Fatalf("%s: %v", tt.name, err)
+				}
+				var got []string
+				for _, order := range result {
+					got = append(got, order.OrderID)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s: Query = %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"go.dtapp.net/gojson"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// CheckpointStore 订单同步进度存储
+type CheckpointStore interface {
+	// Load 获取同步进度，没有进度时返回零值
+	Load(ctx context.Context, key string) (time.Time, error)
+	// Save 保存同步进度
+	Save(ctx context.Context, key string, checkpoint time.Time) error
+}
+
+// MemoryCheckpointStore 内存同步进度存储，进程重启后丢失
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]time.Time
+}
+
+// NewMemoryCheckpointStore 创建内存同步进度存储
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]time.Time)}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, key string) (time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[key], nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, key string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[key] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore 文件同步进度存储，以 JSON 保存所有键的进度，写入时先写临时文件再替换
+type FileCheckpointStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileCheckpointStore 创建文件同步进度存储
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// 读取所有进度，键对应10位时间戳
+func (s *FileCheckpointStore) read() (map[string]int64, error) {
+	checkpoints := make(map[string]int64)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return checkpoints, nil
+	}
+	if err = gojson.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func (s *FileCheckpointStore) Load(ctx context.Context, key string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return time.Time{}, err
+	}
+	ts, ok := checkpoints[key]
+	if !ok {
+		return time.Time{}, nil
+	}
+	return time.Unix(ts, 0), nil
+}
+
+func (s *FileCheckpointStore) Save(ctx context.Context, key string, checkpoint time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = checkpoint.Unix()
+	data, err := gojson.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
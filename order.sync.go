@@ -0,0 +1,170 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gojson"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OrderSyncEventType 订单同步事件类型
+type OrderSyncEventType string
+
+const (
+	OrderSyncCreated OrderSyncEventType = "created" // 新订单，支付时间晚于上次同步进度
+	OrderSyncUpdated OrderSyncEventType = "updated" // 订单有更新，例如状态变化、退款、佣金调整
+)
+
+// OrderSyncEvent 订单同步事件
+type OrderSyncEvent struct {
+	Type  OrderSyncEventType // 事件类型
+	Order ApiOrderListOrder  // 订单
+}
+
+// OrderSyncHandler 订单同步事件处理，返回错误时本次同步中止且不保存同步进度
+type OrderSyncHandler func(ctx context.Context, event OrderSyncEvent) error
+
+// OrderSyncConfig 订单同步配置
+type OrderSyncConfig struct {
+	Key             string           // 同步进度的键，默认为 appkey
+	Store           CheckpointStore  // 同步进度存储，默认使用内存存储
+	Handler         OrderSyncHandler // 订单同步事件处理
+	Overlap         time.Duration    // 每次同步向前重叠的时长，用于获取延迟写入的更新，默认5分钟
+	InitialLookback time.Duration    // 没有同步进度时向前查询的时长，默认1小时
+	BusinessLine    BusinessLine     // 业务线，为0时不限制
+}
+
+// OrderSyncStats 单次同步统计
+type OrderSyncStats struct {
+	StartTime time.Time // 查询起始时间
+	EndTime   time.Time // 查询截止时间，同步成功后保存为同步进度
+	Fetched   int       // 获取的订单数量
+	Created   int       // 新订单事件数量
+	Updated   int       // 订单更新事件数量
+	Skipped   int       // 重叠时间内内容没有变化而跳过的订单数量
+}
+
+// OrderSyncer 按订单更新时间增量同步订单
+// 每次从同步进度向前重叠一段时间开始查询，重叠时间内内容没有变化的订单不会重复产生事件
+// 已经处理过的订单只保存在内存中，进程重启后重叠时间内的订单会再次产生 OrderSyncUpdated 事件，事件处理需要幂等
+type OrderSyncer struct {
+	client *Client
+	config OrderSyncConfig
+
+	mu   sync.Mutex          // 同一时间只执行一次同步
+	seen map[string]syncSeen // 重叠时间内已经处理过的订单，不持久化
+}
+
+// 已经处理过的订单
+type syncSeen struct {
+	fingerprint string    // 订单内容
+	until       time.Time // 超过该时间后不会再出现在重叠时间内
+}
+
+// NewOrderSyncer 创建订单同步
+func (c *Client) NewOrderSyncer(config OrderSyncConfig) *OrderSyncer {
+	if config.Key == "" {
+		config.Key = c.GetAppKey()
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryCheckpointStore()
+	}
+	if config.Overlap <= 0 {
+		config.Overlap = 5 * time.Minute
+	}
+	if config.InitialLookback <= 0 {
+		config.InitialLookback = time.Hour
+	}
+	return &OrderSyncer{client: c, config: config, seen: make(map[string]syncSeen)}
+}
+
+// Sync 执行一次同步，查询从上次同步进度到当前时间更新的订单
+func (s *OrderSyncer) Sync(ctx context.Context) (stats OrderSyncStats, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, err := s.config.Store.Load(ctx, s.config.Key)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.EndTime = time.Now().Truncate(time.Second)
+	if checkpoint.IsZero() {
+		stats.StartTime = stats.EndTime.Add(-s.config.InitialLookback)
+	} else {
+		stats.StartTime = checkpoint.Add(-s.config.Overlap)
+	}
+
+	for _, w := range splitBackfillWindows(stats.StartTime, stats.EndTime, ApiOrderListMaxWindow) {
+		it := s.client.IterateOrders(ctx, ApiOrderListRequest{
+			StartTime:     w.start,
+			EndTime:       w.end,
+			QueryTimeType: OrderQueryTimeTypeMod,
+			BusinessLine:  s.config.BusinessLine,
+		})
+		for it.Next() {
+			stats.Fetched++
+			if err = s.handle(ctx, it.Order(), checkpoint, stats.EndTime, &stats); err != nil {
+				it.Close()
+				return stats, err
+			}
+		}
+		if err = it.Err(); err != nil {
+			return stats, err
+		}
+	}
+
+	// 清理已经超出重叠时间的订单
+	for orderID, seen := range s.seen {
+		if seen.until.Before(stats.EndTime) {
+			delete(s.seen, orderID)
+		}
+	}
+
+	return stats, s.config.Store.Save(ctx, s.config.Key, stats.EndTime)
+}
+
+// 处理一个订单
+func (s *OrderSyncer) handle(ctx context.Context, order ApiOrderListOrder, checkpoint time.Time, end time.Time, stats *OrderSyncStats) error {
+	fingerprint := gojson.JsonEncodeNoError(order)
+	seen, seenBefore := s.seen[order.Orderid]
+	if seenBefore && seen.fingerprint == fingerprint {
+		stats.Skipped++
+		return nil
+	}
+
+	event := OrderSyncEvent{Type: OrderSyncUpdated, Order: order}
+	if payTime, err := strconv.ParseInt(order.Paytime, 10, 64); err == nil && !seenBefore && !time.Unix(payTime, 0).Before(checkpoint) {
+		event.Type = OrderSyncCreated
+	}
+	if s.config.Handler != nil {
+		if err := s.config.Handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	if event.Type == OrderSyncCreated {
+		stats.Created++
+	} else {
+		stats.Updated++
+	}
+
+	s.seen[order.Orderid] = syncSeen{fingerprint: fingerprint, until: end.Add(s.config.Overlap)}
+	return nil
+}
+
+// Run 按间隔持续同步，直到上下文取消，onError 不为空时接收每次同步的错误
+func (s *OrderSyncer) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Sync(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
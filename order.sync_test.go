@@ -0,0 +1,240 @@
+package meituan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// 订单同步测试服务，返回当前设置的订单并记录查询条件
+type orderSyncStub struct {
+	mu      sync.Mutex
+	orders  []ApiOrderListOrder
+	queries []map[string]string
+}
+
+func (s *orderSyncStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := make(map[string]string)
+	for k := range r.URL.Query() {
+		query[k] = r.URL.Query().Get(k)
+	}
+	s.queries = append(s.queries, query)
+	_ = json.NewEncoder(w).Encode(ApiOrderListResponse{DataList: s.orders, Total: len(s.orders)})
+}
+
+// 设置返回的订单，清空查询条件
+func (s *orderSyncStub) set(orders ...ApiOrderListOrder) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orders, s.queries = orders, nil
+}
+
+// 指定支付时间的订单
+func syncOrder(orderID string, payTime time.Time, status OrderStatus) ApiOrderListOrder {
+	return ApiOrderListOrder{Orderid: orderID, Paytime: strconv.FormatInt(payTime.Unix(), 10), Status: int(status)}
+}
+
+func TestOrderSyncer(t *testing.T) {
+	stub := &orderSyncStub{}
+	c := newTestClient(t, stub)
+	store := NewMemoryCheckpointStore()
+	checkpoint := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	if err := store.Save(context.Background(), "appkey", checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []OrderSyncEvent
+	syncer := c.NewOrderSyncer(OrderSyncConfig{
+		Store:        store,
+		Overlap:      time.Minute,
+		BusinessLine: BusinessLineWaimai,
+		Handler: func(ctx context.Context, event OrderSyncEvent) error {
+			events = append(events, event)
+			return nil
+		},
+	})
+
+	// 支付时间不早于同步进度的是新订单，之前支付的是订单更新
+	stub.set(
+		syncOrder("new", checkpoint, OrderStatusPaid),
+		syncOrder("old", checkpoint.Add(-time.Hour), OrderStatusCompleted),
+	)
+	stats, err := syncer.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.StartTime.Equal(checkpoint.Add(-time.Minute)) || stats.Fetched != 2 || stats.Created != 1 || stats.Updated != 1 {
+		t.Errorf("统计 %+v", stats)
+	}
+	if len(events) != 2 || events[0].Type != OrderSyncCreated || events[1].Type != OrderSyncUpdated {
+		t.Errorf("事件 %+v", events)
+	}
+	query := stub.queries[0]
+	if query["startTime"] != strconv.FormatInt(checkpoint.Add(-time.Minute).Unix(), 10) || query["endTime"] != strconv.FormatInt(stats.EndTime.Unix(), 10) || query["queryTimeType"] != "2" || query["businessLine"] != "4" {
+		t.Errorf("查询条件 %v", query)
+	}
+	if saved, _ := store.Load(context.Background(), "appkey"); !saved.Equal(stats.EndTime) {
+		t.Errorf("同步进度 %v, want %v", saved, stats.EndTime)
+	}
+
+	// 重叠时间内内容没有变化的订单跳过，有变化的产生更新事件
+	events = nil
+	stub.set(
+		syncOrder("new", checkpoint, OrderStatusCompleted),
+		syncOrder("old", checkpoint.Add(-time.Hour), OrderStatusCompleted),
+	)
+	if stats, err = syncer.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Created != 0 || stats.Updated != 1 || stats.Skipped != 1 {
+		t.Errorf("统计 %+v", stats)
+	}
+	if len(events) != 1 || events[0].Type != OrderSyncUpdated || events[0].Order.Orderid != "new" {
+		t.Errorf("事件 %+v", events)
+	}
+
+	// 重启后不知道已经处理过的订单，重叠时间内的订单再次产生更新事件
+	events = nil
+	syncer = c.NewOrderSyncer(syncer.config)
+	if stats, err = syncer.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Created != 0 || stats.Updated != 2 || stats.Skipped != 0 {
+		t.Errorf("重启后统计 %+v", stats)
+	}
+}
+
+func TestOrderSyncerInitialLookback(t *testing.T) {
+	stub := &orderSyncStub{}
+	c := newTestClient(t, stub)
+	syncer := c.NewOrderSyncer(OrderSyncConfig{InitialLookback: 30 * time.Hour})
+
+	stats, err := syncer.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stats.StartTime.Equal(stats.EndTime.Add(-30 * time.Hour)) {
+		t.Errorf("统计 %+v", stats)
+	}
+	// 超过接口最大时间范围时分多个时间窗口查询
+	if len(stub.queries) != 2 || stub.queries[1]["endTime"] != strconv.FormatInt(stats.EndTime.Unix(), 10) {
+		t.Errorf("查询条件 %v", stub.queries)
+	}
+}
+
+func TestOrderSyncerHandlerError(t *testing.T) {
+	stub := &orderSyncStub{}
+	c := newTestClient(t, stub)
+	store := NewMemoryCheckpointStore()
+	checkpoint := time.Now().Add(-10 * time.Minute).Truncate(time.Second)
+	if err := store.Save(context.Background(), "sync", checkpoint); err != nil {
+		t.Fatal(err)
+	}
+
+	errHandler := errors.New("处理失败")
+	fail := true
+	var handled []string
+	syncer := c.NewOrderSyncer(OrderSyncConfig{
+		Key:   "sync",
+		Store: store,
+		Handler: func(ctx context.Context, event OrderSyncEvent) error {
+			handled = append(handled, event.Order.Orderid)
+			if fail && event.Order.Orderid == "2" {
+				return errHandler
+			}
+			return nil
+		},
+	})
+	stub.set(syncOrder("1", checkpoint, OrderStatusPaid), syncOrder("2", checkpoint, OrderStatusPaid))
+
+	// 处理失败时不保存同步进度
+	if _, err := syncer.Sync(context.Background()); !errors.Is(err, errHandler) {
+		t.Fatalf("err = %v", err)
+	}
+	if saved, _ := store.Load(context.Background(), "sync"); !saved.Equal(checkpoint) {
+		t.Errorf("处理失败后同步进度 %v, want %v", saved, checkpoint)
+	}
+
+	// 下次同步从原来的进度开始，处理失败的订单再次产生事件
+	fail, handled = false, nil
+	stats, err := syncer.Sync(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(handled) != 1 || handled[0] != "2" || stats.Skipped != 1 || stats.Created != 1 {
+		t.Errorf("处理的订单 %v, 统计 %+v", handled, stats)
+	}
+	if !stats.StartTime.Equal(checkpoint.Add(-5 * time.Minute)) {
+		t.Errorf("StartTime = %v", stats.StartTime)
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	store := NewFileCheckpointStore(path)
+
+	// 文件不存在时没有进度
+	if checkpoint, err := store.Load(ctx, "a"); err != nil || !checkpoint.IsZero() {
+		t.Fatalf("checkpoint = %v, err = %v", checkpoint, err)
+	}
+
+	a, b := time.Unix(1700000000, 0), time.Unix(1700003600, 0)
+	if err := store.Save(ctx, "a", a); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(ctx, "b", b); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(ctx, "a", a.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 重新打开后读取所有键的进度
+	store = NewFileCheckpointStore(path)
+	if checkpoint, err := store.Load(ctx, "a"); err != nil || !checkpoint.Equal(a.Add(time.Minute)) {
+		t.Errorf("a = %v, err = %v", checkpoint, err)
+	}
+	if checkpoint, err := store.Load(ctx, "b"); err != nil || !checkpoint.Equal(b) {
+		t.Errorf("b = %v, err = %v", checkpoint, err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"a":1700000060,"b":1700003600}` {
+		t.Errorf("文件内容 %s", data)
+	}
+
+	// 替换后不残留临时文件
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("目录中的文件 %v", entries)
+	}
+
+	// 文件内容损坏时不覆盖
+	if err = os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Load(ctx, "a"); err == nil {
+		t.Error("读取损坏的文件应返回错误")
+	}
+	if err = store.Save(ctx, "a", a); err == nil {
+		t.Error("保存到损坏的文件应返回错误")
+	}
+	if data, _ = os.ReadFile(path); string(data) != "{" {
+		t.Errorf("损坏的文件被覆盖 %s", data)
+	}
+}
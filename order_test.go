@@ -0,0 +1,166 @@
+package meituan
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestApiOrderListOrderConvert(t *testing.T) {
+	tests := []struct {
+		name  string
+		order ApiOrderListOrder
+		want  Order
+	}{
+		{
+			name: "退款信息在扩展信息中",
+			order: ApiOrderListOrder{
+				Orderid:       "1001",
+				BusinessLine:  4,
+				Paytime:       "1700000000",
+				ModTime:       "1700090000",
+				Payprice:      "90.00",
+				Profit:        "4.50",
+				Status:        8,
+				TradeTypeList: []int{2},
+				Extra:         `{"quantity":3,"coupon_code":"C1001","refundInfoList":[{"refundPrice":"30.00","refundTime":"1700080000"},{"refundPrice":"10.00","refundTime":"1700050000"}],"refundProfitList":[{"refundProfit":"1.50"},{"refundProfit":"0.50"}],"consumeProfitList":[{"consumeProfit":"1.20"},{"consumeProfit":"0.80"}]}`,
+			},
+			want: Order{
+				OrderID:       "1001",
+				Source:        OrderSourceList,
+				BusinessLine:  BusinessLineWaimai,
+				Quantity:      3,
+				Status:        OrderStatusCompleted,
+				TradeTypes:    []TradeType{TradeTypeCps},
+				PayTime:       time.Unix(1700000000, 0),
+				ModTime:       time.Unix(1700090000, 0),
+				RefundTime:    time.Unix(1700080000, 0),
+				PayPrice:      9000,
+				Profit:        450,
+				RefundPrice:   4000,
+				RefundProfit:  200,
+				ConsumeProfit: 200,
+				CouponCode:    "C1001",
+			},
+		},
+		{
+			name: "优先使用订单的退款信息",
+			order: ApiOrderListOrder{
+				Orderid:      "1002",
+				Payprice:     "90.00",
+				Refundprice:  "60.00",
+				Refundtime:   "1700090000",
+				Refundprofit: "3.00",
+				Status:       9,
+				RiskOrder:    1,
+				Extra:        `{"refundInfoList":[{"refundPrice":"30.00","refundTime":"1700080000"}],"refundProfitList":[{"refundProfit":"1.50"}]}`,
+			},
+			want: Order{
+				OrderID:      "1002",
+				Source:       OrderSourceList,
+				Status:       OrderStatusRefunded,
+				Risk:         true,
+				RefundTime:   time.Unix(1700090000, 0),
+				PayPrice:     9000,
+				RefundPrice:  6000,
+				RefundProfit: 300,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := tt.order.Order()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(order, tt.want) {
+				t.Errorf("订单\n%+v\nwant\n%+v", order, tt.want)
+			}
+		})
+	}
+
+	// 扩展信息或金额格式错误
+	for _, o := range []ApiOrderListOrder{
+		{Orderid: "1", Extra: "{"},
+		{Orderid: "1", Extra: `{"consumeProfitList":[{"consumeProfit":"x"}]}`},
+		{Orderid: "1", ModTime: "x"},
+	} {
+		if _, err := o.Order(); err == nil {
+			t.Errorf("%+v 应返回错误", o)
+		}
+	}
+}
+
+func TestApiOrderResponseConvert(t *testing.T) {
+	var response ApiOrderResponse
+	decodeFixture(t, "api_order_partial_consume.json", &response)
+	order, err := response.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ConsumeProfit != 150 || order.RefundPrice != 0 || !order.RefundTime.IsZero() {
+		t.Errorf("订单 %+v", order)
+	}
+
+	// 没有退款佣金时使用退款佣金列表
+	response.Data.Refundprofit = ""
+	response.Data.RefundProfitList = []ApiOrderRefundProfit{{RefundProfit: "0.30"}, {RefundProfit: "0.20"}}
+	if order, err = response.Order(); err != nil || order.RefundProfit != 50 {
+		t.Errorf("RefundProfit = %s, err = %v", order.RefundProfit, err)
+	}
+}
+
+func TestServeHttpOrderHttpRequestConvert(t *testing.T) {
+	callback := ServeHttpOrderHttpRequest{
+		Orderid:         "1001",
+		Appkey:          "appkey",
+		Sid:             "sid1",
+		Uid:             "uid1",
+		ActId:           "33",
+		BusinessLine:    "4",
+		SubBusinessLine: "1",
+		Type:            "4",
+		Smstitle:        "外卖",
+		Quantity:        "2",
+		Status:          "1",
+		TradeTypeList:   "[2,3]",
+		Ordertime:       "2023-11-15 06:00:00",
+		Paytime:         "1700000000",
+		ModTime:         "1700000100",
+		Total:           "30.00",
+		PayPrice:        "25.80",
+	}
+	order, err := callback.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Order{
+		OrderID:         "1001",
+		Source:          OrderSourceCallback,
+		AppKey:          "appkey",
+		Sid:             "sid1",
+		Uid:             "uid1",
+		ActID:           33,
+		BusinessLine:    BusinessLineWaimai,
+		SubBusinessLine: 1,
+		Type:            "4",
+		Title:           "外卖",
+		Quantity:        2,
+		Status:          OrderStatusPaid,
+		TradeTypes:      []TradeType{TradeTypeCps, TradeTypeFirstBuy},
+		OrderTime:       time.Date(2023, 11, 15, 6, 0, 0, 0, orderLocation),
+		PayTime:         time.Unix(1700000000, 0),
+		ModTime:         time.Unix(1700000100, 0),
+		Total:           3000,
+		PayPrice:        2580,
+	}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("订单\n%+v\nwant\n%+v", order, want)
+	}
+
+	// 数字字段格式错误
+	callback.Quantity = "x"
+	if _, err = callback.Order(); err == nil {
+		t.Error("quantity 格式错误时应返回错误")
+	}
+}
@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type PoiAreaResponse struct {
@@ -32,16 +31,11 @@ func newPoiAreaResult(result PoiAreaResponse, body []byte, http gorequest.Respon
 // https://openapi.meituan.com/#api-0.%E5%9F%BA%E7%A1%80%E6%95%B0%E6%8D%AE-GetHttpsOpenapiMeituanComPoiAreaCityid1
 func (c *Client) PoiArea(ctx context.Context, cityID int, notMustParams ...gorequest.Params) (*PoiAreaResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "poi/area")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
 	params.Set("cityid", cityID)
 
 	// 请求
-	var response PoiAreaResponse
-	request, err := c.request(ctx, "poi/area", params, http.MethodGet, &response)
+	response, request, err := call[PoiAreaResponse](ctx, c, endpointPoiArea, params)
 	return newPoiAreaResult(response, request.ResponseBody, request), err
 }
@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type PoiCategoryResponse struct {
@@ -32,16 +31,11 @@ func newPoiCategoryResult(result PoiCategoryResponse, body []byte, http goreques
 // https://openapi.meituan.com/#api-0.%E5%9F%BA%E7%A1%80%E6%95%B0%E6%8D%AE-GetHttpsOpenapiMeituanComPoiDistrictCityid1
 func (c *Client) PoiCategory(ctx context.Context, cityID int, notMustParams ...gorequest.Params) (*PoiCategoryResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "poi/category")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
 	params.Set("cityid", cityID)
 
 	// 请求
-	var response PoiCategoryResponse
-	request, err := c.request(ctx, "poi/category", params, http.MethodGet, &response)
+	response, request, err := call[PoiCategoryResponse](ctx, c, endpointPoiCategory, params)
 	return newPoiCategoryResult(response, request.ResponseBody, request), err
 }
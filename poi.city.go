@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type PoiCityResponse struct {
@@ -29,15 +28,10 @@ func newPoiCityResult(result PoiCityResponse, body []byte, http gorequest.Respon
 // https://openapi.meituan.com/#api-0.%E5%9F%BA%E7%A1%80%E6%95%B0%E6%8D%AE-GetHttpsOpenapiMeituanComPoiCity
 func (c *Client) PoiCity(ctx context.Context, notMustParams ...gorequest.Params) (*PoiCityResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "poi/city")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
 
 	// 请求
-	var response PoiCityResponse
-	request, err := c.request(ctx, "poi/city", params, http.MethodGet, &response)
+	response, request, err := call[PoiCityResponse](ctx, c, endpointPoiCity, params)
 	return newPoiCityResult(response, request.ResponseBody, request), err
 }
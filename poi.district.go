@@ -3,7 +3,6 @@ package meituan
 import (
 	"context"
 	"go.dtapp.net/gorequest"
-	"net/http"
 )
 
 type PoiDistrictResponse struct {
@@ -28,16 +27,11 @@ func newPoiDistrictResult(result PoiDistrictResponse, body []byte, http goreques
 // https://openapi.meituan.com/#api-0.%E5%9F%BA%E7%A1%80%E6%95%B0%E6%8D%AE-GetHttpsOpenapiMeituanComPoiDistrictCityid1
 func (c *Client) PoiDistrict(ctx context.Context, cityID int, notMustParams ...gorequest.Params) (*PoiDistrictResult, error) {
 
-	// OpenTelemetry链路追踪
-	ctx = c.TraceStartSpan(ctx, "poi/district")
-	defer c.TraceEndSpan()
-
 	// 参数
 	params := gorequest.NewParamsWith(notMustParams...)
 	params.Set("cityid", cityID)
 
 	// 请求
-	var response PoiDistrictResponse
-	request, err := c.request(ctx, "poi/district", params, http.MethodGet, &response)
+	response, request, err := call[PoiDistrictResponse](ctx, c, endpointPoiDistrict, params)
 	return newPoiDistrictResult(response, request.ResponseBody, request), err
 }
@@ -6,42 +6,102 @@ import (
 	"go.dtapp.net/gorequest"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"reflect"
 )
 
-func (c *Client) request(ctx context.Context, url string, param gorequest.Params, method string, response any) (gorequest.Response, error) {
+// 返回内容中的业务状态，联盟接口使用 status，开放平台接口和CPS开放接口使用 code
+type responseEnvelope struct {
+	Status  *int   `json:"status"`
+	Des     string `json:"des"`
+	Code    *int   `json:"code"`
+	Msg     string `json:"msg"`
+	Message string `json:"message"`
+}
+
+func (c *Client) request(ctx context.Context, ep endpoint, param gorequest.Params, response any) (gorequest.Response, error) {
 
 	// 请求地址
-	uri := apiUrl + url
+	uri := c.getUrl(ep.path)
 
-	// 设置请求地址
-	c.httpClient.SetUri(uri)
+	// OpenTelemetry链路追踪
+	c.TraceSetAttributes(ctx, attribute.String("http.url", uri))
+	c.TraceSetAttributes(ctx, attribute.String("http.method", ep.method))
+	c.TraceSetAttributes(ctx, attribute.String("http.params", gojson.JsonEncodeNoError(param)))
 
-	// 设置方式
-	c.httpClient.SetMethod(method)
+	for attempt := 1; ; attempt++ {
 
-	// 设置格式
-	c.httpClient.SetContentTypeJson()
+		// 限流等待
+		if c.rateLimiter != nil {
+			wait, err := c.rateLimiter.wait(ctx, c.GetAppKey(), ep.path)
+			if err != nil {
+				c.TraceRecordError(ctx, err)
+				c.TraceSetStatus(ctx, codes.Error, err.Error())
+				return gorequest.Response{}, err
+			}
+			if wait > 0 {
+				c.TraceAddEvent(ctx, "meituan.rate_limit_wait", attribute.Int64("meituan.wait_ms", wait.Milliseconds()))
+			}
+		}
 
-	// 设置参数
-	c.httpClient.SetParams(param)
+		request, err := c.requestAttempt(ctx, ep, uri, c.prepareParams(ep, param), response)
+		if err == nil || attempt >= c.retry.MaxAttempts || !shouldRetry(ctx, err) {
+			c.TraceSetAttributes(ctx, attribute.Int("meituan.attempts", attempt))
+			if err != nil {
+				c.TraceRecordError(ctx, err)
+				c.TraceSetStatus(ctx, codes.Error, err.Error())
+			}
+			return request, err
+		}
 
-	// OpenTelemetry链路追踪
-	c.TraceSetAttributes(attribute.String("http.url", uri))
-	c.TraceSetAttributes(attribute.String("http.method", method))
-	c.TraceSetAttributes(attribute.String("http.params", gojson.JsonEncodeNoError(param)))
+		// 等待重试
+		backoff := c.retry.backoff(attempt)
+		c.TraceAddEvent(ctx, "meituan.retry",
+			attribute.Int("meituan.attempt", attempt),
+			attribute.Int64("meituan.backoff_ms", backoff.Milliseconds()),
+			attribute.String("meituan.error", err.Error()),
+		)
+		if sleepErr := sleepContext(ctx, backoff); sleepErr != nil {
+			// 等待时上下文取消，返回最后一次请求的错误
+			c.TraceSetAttributes(ctx, attribute.Int("meituan.attempts", attempt))
+			c.TraceRecordError(ctx, err)
+			c.TraceSetStatus(ctx, codes.Error, err.Error())
+			return request, err
+		}
+
+		// 清空上次请求解析的内容
+		if v := reflect.ValueOf(response); v.Kind() == reflect.Pointer && !v.IsNil() {
+			v.Elem().SetZero()
+		}
+	}
+}
+
+// 发起一次请求并解析返回内容
+func (c *Client) requestAttempt(ctx context.Context, ep endpoint, uri string, param gorequest.Params, response any) (gorequest.Response, error) {
 
 	// 发起请求
-	request, err := c.httpClient.Request(ctx)
+	request, err := c.doHttp(ctx, ep.method, uri, param, ep.sign == signHeader)
 	if err != nil {
-		c.TraceRecordError(err)
-		c.TraceSetStatus(codes.Error, err.Error())
 		return gorequest.Response{}, err
 	}
 
+	// OpenTelemetry链路追踪
+	c.TraceSetAttributes(ctx, attribute.Int("http.status_code", request.ResponseStatusCode))
+
+	// 业务状态
+	var envelope responseEnvelope
+	_ = gojson.Unmarshal(request.ResponseBody, &envelope)
+
+	// OpenTelemetry链路追踪
+	if envelope.Status != nil {
+		c.TraceSetAttributes(ctx, attribute.Int("meituan.status", *envelope.Status))
+	}
+	if envelope.Code != nil {
+		c.TraceSetAttributes(ctx, attribute.Int("meituan.code", *envelope.Code))
+	}
+
 	err = gojson.Unmarshal(request.ResponseBody, &response)
-	if err != nil {
-		c.TraceRecordError(err)
-		c.TraceSetStatus(codes.Error, err.Error())
+	if apiErr := newAPIError(ep, request.ResponseStatusCode, request.ResponseBody, envelope, c.errorClassifier); apiErr != nil {
+		err = apiErr
 	}
 
 	return request, err
@@ -0,0 +1,86 @@
+package meituan
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// DedupStore 订单回推去重存储
+type DedupStore interface {
+	// Claim 占用键，键已存在且未过期时返回 false
+	Claim(ctx context.Context, key string) (bool, error)
+	// Release 释放键，处理失败后调用，美团重试时可以再次处理
+	Release(ctx context.Context, key string) error
+}
+
+// DedupKey 订单回推的去重键，由订单id、订单状态和修改时间组成
+// 美团重试推送时三者相同，订单状态变化或信息修改后的推送不会被去重
+func (r *ServeHttpOrderHttpRequest) DedupKey() string {
+	return r.Orderid + "|" + r.Status + "|" + r.ModTime
+}
+
+// MemoryDedupStore 内存去重存储，超过容量时淘汰最久未使用的键，键在有效期后过期
+type MemoryDedupStore struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	items    map[string]*list.Element
+	order    *list.List // 最近使用的键在前
+}
+
+// 内存去重存储的键
+type dedupEntry struct {
+	key     string
+	expires time.Time
+}
+
+// NewMemoryDedupStore 创建内存去重存储，capacity 为最多保存的键数量，ttl 为键的有效期，为0时不过期
+func NewMemoryDedupStore(capacity int, ttl time.Duration) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		capacity: capacity,
+		ttl:      ttl,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (s *MemoryDedupStore) Claim(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if element, ok := s.items[key]; ok {
+		entry := element.Value.(*dedupEntry)
+		if entry.expires.IsZero() || now.Before(entry.expires) {
+			s.order.MoveToFront(element)
+			return false, nil
+		}
+		s.remove(element)
+	}
+
+	entry := &dedupEntry{key: key}
+	if s.ttl > 0 {
+		entry.expires = now.Add(s.ttl)
+	}
+	s.items[key] = s.order.PushFront(entry)
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		s.remove(s.order.Back())
+	}
+	return true, nil
+}
+
+func (s *MemoryDedupStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.items[key]; ok {
+		s.remove(element)
+	}
+	return nil
+}
+
+func (s *MemoryDedupStore) remove(element *list.Element) {
+	s.order.Remove(element)
+	delete(s.items, element.Value.(*dedupEntry).key)
+}
@@ -0,0 +1,79 @@
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+	"time"
+)
+
+// 订单回推去重表
+const dedupTable = LogTable + "_callback_dedup"
+
+// SQLDedupStore 基于 database/sql 的去重存储，支持 SQLite 和 PostgreSQL
+// 使用前需要调用 MigrateSQL 创建数据表，驱动由调用方引入
+// 设置有效期时，Claim 每隔一个有效期删除一次过期的键，也可以调用 Purge 主动删除
+type SQLDedupStore struct {
+	db        *sql.DB
+	dialect   SQLDialect
+	ttl       time.Duration
+	lastPurge atomic.Int64 // 上次删除过期键的时间
+}
+
+// NewSQLDedupStore 创建数据库去重存储，ttl 为键的有效期，为0时不过期
+func NewSQLDedupStore(db *sql.DB, dialect SQLDialect, ttl time.Duration) (*SQLDedupStore, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	return &SQLDedupStore{db: db, dialect: dialect, ttl: ttl}, nil
+}
+
+// Claim 插入键，键已存在时只有过期才覆盖
+func (s *SQLDedupStore) Claim(ctx context.Context, key string) (bool, error) {
+	now := time.Now()
+	if s.ttl > 0 {
+		last := s.lastPurge.Load()
+		if now.Unix()-last >= int64(s.ttl/time.Second) && s.lastPurge.CompareAndSwap(last, now.Unix()) {
+			if _, err := s.Purge(ctx); err != nil {
+				return false, err
+			}
+		}
+	}
+	var expiredBefore int64
+	if s.ttl > 0 {
+		expiredBefore = now.Add(-s.ttl).Unix()
+	}
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind(
+		"INSERT INTO "+dedupTable+" (dedup_key, created_at) VALUES (?, ?)"+
+			" ON CONFLICT (dedup_key) DO UPDATE SET created_at = excluded.created_at"+
+			" WHERE "+dedupTable+".created_at < ?",
+	), key, now.Unix(), expiredBefore)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (s *SQLDedupStore) Release(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, s.dialect.rebind("DELETE FROM "+dedupTable+" WHERE dedup_key = ?"), key)
+	return err
+}
+
+// Purge 删除过期的键，返回删除的数量，没有设置有效期时不删除
+func (s *SQLDedupStore) Purge(ctx context.Context) (int64, error) {
+	if s.ttl <= 0 {
+		return 0, nil
+	}
+	result, err := s.db.ExecContext(ctx, s.dialect.rebind("DELETE FROM "+dedupTable+" WHERE created_at < ?"), time.Now().Add(-s.ttl).Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+var _ DedupStore = (*SQLDedupStore)(nil)
+var _ DedupStore = (*MemoryDedupStore)(nil)
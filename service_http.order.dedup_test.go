@@ -0,0 +1,162 @@
+package meituan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// 检查 Claim 的结果
+func checkClaim(t *testing.T, store DedupStore, key string, want bool) {
+	t.Helper()
+	claimed, err := store.Claim(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed != want {
+		t.Errorf("Claim(%s) = %v, want %v", key, claimed, want)
+	}
+}
+
+func TestMemoryDedupStoreEviction(t *testing.T) {
+	store := NewMemoryDedupStore(2, 0)
+	checkClaim(t, store, "a", true)
+	checkClaim(t, store, "b", true)
+	// 重复的键更新为最近使用
+	checkClaim(t, store, "a", false)
+	// 超过容量时淘汰最久未使用的 b
+	checkClaim(t, store, "c", true)
+	checkClaim(t, store, "a", false)
+	checkClaim(t, store, "b", true)
+	checkClaim(t, store, "c", true)
+
+	// 释放后可以再次占用
+	if err := store.Release(context.Background(), "c"); err != nil {
+		t.Fatal(err)
+	}
+	checkClaim(t, store, "c", true)
+}
+
+func TestMemoryDedupStoreTTL(t *testing.T) {
+	store := NewMemoryDedupStore(0, 20*time.Millisecond)
+	checkClaim(t, store, "a", true)
+	checkClaim(t, store, "a", false)
+	time.Sleep(30 * time.Millisecond)
+	// 过期后可以再次占用
+	checkClaim(t, store, "a", true)
+	checkClaim(t, store, "a", false)
+}
+
+func TestSQLDedupStore(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	store, err := NewSQLDedupStore(db, SQLDialectSQLite, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkClaim(t, store, "a", true)
+	checkClaim(t, store, "a", false)
+
+	// 释放后可以再次占用
+	if err = store.Release(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+	checkClaim(t, store, "a", true)
+
+	// 过期的键可以再次占用
+	expired := time.Now().Add(-2 * time.Hour).Unix()
+	if _, err = db.ExecContext(ctx, "INSERT INTO "+dedupTable+" (dedup_key, created_at) VALUES (?, ?), (?, ?)", "b", expired, "c", expired); err != nil {
+		t.Fatal(err)
+	}
+	checkClaim(t, store, "b", true)
+	checkClaim(t, store, "b", false)
+
+	// 删除过期的键
+	purged, err := store.Purge(ctx)
+	if err != nil || purged != 1 {
+		t.Errorf("Purge = %d, %v, want 1", purged, err)
+	}
+	var keys int
+	if err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+dedupTable).Scan(&keys); err != nil || keys != 2 {
+		t.Errorf("剩余 %d 个键, %v", keys, err)
+	}
+
+	// 不过期的存储不删除
+	store, err = NewSQLDedupStore(db, SQLDialectSQLite, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkClaim(t, store, "a", false)
+	if purged, err = store.Purge(ctx); err != nil || purged != 0 {
+		t.Errorf("Purge = %d, %v, want 0", purged, err)
+	}
+}
+
+func TestSQLDedupStoreClaimPurges(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	store, err := NewSQLDedupStore(db, SQLDialectSQLite, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.ExecContext(ctx, "INSERT INTO "+dedupTable+" (dedup_key, created_at) VALUES (?, ?)", "old", time.Now().Add(-2*time.Hour).Unix()); err != nil {
+		t.Fatal(err)
+	}
+	// Claim 时删除其他过期的键
+	checkClaim(t, store, "a", true)
+	var keys int
+	if err = db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+dedupTable+" WHERE dedup_key = ?", "old").Scan(&keys); err != nil || keys != 0 {
+		t.Errorf("过期的键 %d 个, %v", keys, err)
+	}
+}
+
+func TestOrderCallbackHandlerDedup(t *testing.T) {
+	c := newCallbackTestClient(t)
+	body, err := json.Marshal(signedCallbackParams(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		calls int
+		fail  bool
+	)
+	handler := c.OrderCallbackHandler(func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+		calls++
+		if fail {
+			return errors.New("处理失败")
+		}
+		return nil
+	})
+	handler.Dedup = NewMemoryDedupStore(10, 0)
+	post := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(body))))
+		return w
+	}
+
+	// 处理失败时美团重试推送会再次处理
+	fail = true
+	if w := post(); w.Code != http.StatusInternalServerError || calls != 1 {
+		t.Fatalf("处理失败 status = %d, 处理 %d 次", w.Code, calls)
+	}
+	fail = false
+	if w := post(); w.Code != http.StatusOK || calls != 2 {
+		t.Fatalf("重试 status = %d, 处理 %d 次", w.Code, calls)
+	}
+
+	// 处理成功后重复的回推直接回复成功
+	w := post()
+	var reply ServeHttpOrderHttpResponse
+	if err = json.Unmarshal(w.Body.Bytes(), &reply); err != nil || w.Code != http.StatusOK || reply.Errcode != 0 {
+		t.Errorf("重复推送 status = %d, body = %s, %v", w.Code, w.Body, err)
+	}
+	if calls != 2 {
+		t.Errorf("重复推送时处理函数被调用, 共 %d 次", calls)
+	}
+}
@@ -1,9 +1,15 @@
 package meituan
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"go.dtapp.net/gojson"
+	"go.dtapp.net/gorequest"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 )
 
 // ServeHttpOrderHttpRequest 请求参数
@@ -34,6 +40,9 @@ type ServeHttpOrderHttpRequest struct {
 	ConsumeType         string `json:"consumeType,omitempty"`         // 核销类型
 	RefundType          string `json:"refundType,omitempty"`          // 退款类型
 	EncryptionVoucherId string `json:"encryptionVoucherId,omitempty"` // 消费券加密券ID
+
+	Raw      []byte           `json:"-"` // 回推的原始内容，GET 请求为查询字符串，用于审计
+	received gorequest.Params // 回推的原始参数，包含未定义的字段，用于校验签名
 }
 
 // ServeHttpOrderHttpResponse 返回参数
@@ -43,10 +52,110 @@ type ServeHttpOrderHttpResponse struct {
 }
 
 // ServeHttpOrderHttp 订单回推接口（新版）
+// 根据请求方法和 Content-Type 解析 JSON、表单或查询字符串，并校验 appkey 和签名，校验失败时返回 *CallbackError
 // https://union.meituan.com/v2/apiDetail?id=22
 func (c *Client) ServeHttpOrderHttp(ctx context.Context, w http.ResponseWriter, r *http.Request) (validateJson ServeHttpOrderHttpRequest, err error) {
-	err = gojson.NewDecoder(r.Body).Decode(&validateJson)
-	return
+	validateJson, err = decodeOrderCallback(r)
+	if err != nil {
+		return validateJson, err
+	}
+	return validateJson, c.VerifyOrderCallback(&validateJson)
+}
+
+// 解析订单回推，GET 请求使用查询字符串，表单请求使用表单，其他请求使用 JSON
+func decodeOrderCallback(r *http.Request) (callback ServeHttpOrderHttpRequest, err error) {
+	if r.Method == http.MethodGet {
+		callback.Raw = []byte(r.URL.RawQuery)
+		return callback, callback.decodeValues(r.URL.Query())
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return callback, err
+	}
+	callback.Raw = body
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return callback, err
+		}
+		return callback, callback.decodeValues(values)
+	}
+
+	return callback, callback.decodeJSON(body)
+}
+
+// 从 JSON 解析，字段值可以是字符串或数字
+func (r *ServeHttpOrderHttpRequest) decodeJSON(data []byte) error {
+	// 数字保持原样，避免大整数转换为浮点数后签名不一致
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&r.received); err != nil {
+		return err
+	}
+	fields := make(map[string]string, len(r.received))
+	for key, value := range r.received {
+		switch v := value.(type) {
+		case nil:
+		case string:
+			fields[key] = v
+		case json.Number:
+			fields[key] = v.String()
+		default:
+			// 布尔值、数组和对象保留 JSON 内容
+			text, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			fields[key] = string(text)
+		}
+	}
+	return r.decodeFields(fields)
+}
+
+// 从表单或查询字符串解析，同名参数取第一个值
+func (r *ServeHttpOrderHttpRequest) decodeValues(values url.Values) error {
+	r.received = gorequest.NewParams()
+	fields := make(map[string]string, len(values))
+	for key := range values {
+		r.received.Set(key, values.Get(key))
+		fields[key] = values.Get(key)
+	}
+	return r.decodeFields(fields)
+}
+
+// 将字符串参数解析到结构体字段
+func (r *ServeHttpOrderHttpRequest) decodeFields(fields map[string]string) error {
+	data, err := gojson.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return gojson.Unmarshal(data, r)
+}
+
+// VerifyOrderCallback 校验订单回推的 appkey 和签名
+// 签名使用回推的全部参数按签名(sign)生成逻辑重新计算
+func (c *Client) VerifyOrderCallback(r *ServeHttpOrderHttpRequest) error {
+	if !c.isCallbackAppKey(r.Appkey) {
+		return &CallbackError{OrderID: r.Orderid, AppKey: r.Appkey, Err: ErrCallbackAppKey}
+	}
+	if r.Sign == "" || !c.VerifySign(r.Params(), r.Sign) {
+		return &CallbackError{OrderID: r.Orderid, AppKey: r.Appkey, Err: ErrCallbackSign}
+	}
+	return nil
+}
+
+// Params 回推参数，与请求接口使用相同的签名方式，可以通过 Client.VerifySign 校验 Sign
+// 通过 ServeHttpOrderHttp 解析时返回回推的原始参数
+func (r *ServeHttpOrderHttpRequest) Params() gorequest.Params {
+	if r.received != nil {
+		return gorequest.NewParamsWith(r.received)
+	}
+	params := gorequest.NewParams()
+	_ = gojson.Unmarshal([]byte(gojson.JsonEncodeNoError(r)), &params)
+	return params
 }
 
 // Success 返回正常
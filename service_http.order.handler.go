@@ -0,0 +1,92 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"go.dtapp.net/gojson"
+	"net/http"
+)
+
+// OrderCallbackMaxBodyBytes 订单回推内容默认的最大长度
+const OrderCallbackMaxBodyBytes = 1 << 20
+
+// OrderCallbackFunc 订单回推处理，返回错误时回复美团处理失败
+type OrderCallbackFunc func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error
+
+// OrderCallback 订单回推的 http.Handler
+// 解析并校验回推内容后调用处理函数，根据处理结果回复 {"errcode":0,"errmsg":"ok"} 或错误
+type OrderCallback struct {
+	client       *Client
+	handler      OrderCallbackFunc
+	MaxBodyBytes int64      // 回推内容的最大长度，默认 OrderCallbackMaxBodyBytes
+	Dedup        DedupStore // 回推去重，重复的回推直接回复成功，不再调用处理函数，为空时不去重
+}
+
+// OrderCallbackHandler 创建订单回推的 http.Handler
+func (c *Client) OrderCallbackHandler(fn OrderCallbackFunc) *OrderCallback {
+	return &OrderCallback{client: c, handler: fn, MaxBodyBytes: OrderCallbackMaxBodyBytes}
+}
+
+func (h *OrderCallback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		writeOrderCallbackReply(w, http.StatusMethodNotAllowed, ServeHttpOrderHttpResponse{Errcode: 1, Errmsg: "method not allowed"})
+		return
+	}
+
+	maxBodyBytes := h.MaxBodyBytes
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = OrderCallbackMaxBodyBytes
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+
+	ctx := r.Context()
+	callback, err := h.client.ServeHttpOrderHttp(ctx, w, r)
+	if err != nil {
+		var (
+			maxBytesErr *http.MaxBytesError
+			callbackErr *CallbackError
+		)
+		switch {
+		case errors.As(err, &maxBytesErr):
+			writeOrderCallbackReply(w, http.StatusRequestEntityTooLarge, ServeHttpOrderHttpResponse{Errcode: 1, Errmsg: "request body too large"})
+		case errors.As(err, &callbackErr):
+			writeOrderCallbackReply(w, http.StatusForbidden, ServeHttpOrderHttpResponse{Errcode: 1, Errmsg: callbackErr.Err.Error()})
+		default:
+			writeOrderCallbackReply(w, http.StatusBadRequest, ServeHttpOrderHttpResponse{Errcode: 1, Errmsg: "invalid request body"})
+		}
+		return
+	}
+
+	// 美团重试推送的相同回推只处理一次
+	if h.Dedup != nil {
+		claimed, err := h.Dedup.Claim(ctx, callback.DedupKey())
+		if err != nil {
+			writeOrderCallbackReply(w, http.StatusInternalServerError, callback.Error())
+			return
+		}
+		if !claimed {
+			writeOrderCallbackReply(w, http.StatusOK, callback.Success())
+			return
+		}
+	}
+
+	if h.handler != nil {
+		if err = h.handler(ctx, &callback); err != nil {
+			// 处理失败时释放去重键，美团重试时再次处理
+			if h.Dedup != nil {
+				_ = h.Dedup.Release(context.WithoutCancel(ctx), callback.DedupKey())
+			}
+			writeOrderCallbackReply(w, http.StatusInternalServerError, callback.Error())
+			return
+		}
+	}
+	writeOrderCallbackReply(w, http.StatusOK, callback.Success())
+}
+
+// 回复美团
+func writeOrderCallbackReply(w http.ResponseWriter, statusCode int, reply ServeHttpOrderHttpResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = gojson.NewEncoder(w).Encode(reply)
+}
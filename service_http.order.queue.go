@@ -0,0 +1,334 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.dtapp.net/gojson"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrCallbackQueueFull 回推处理队列已满
+var ErrCallbackQueueFull = errors.New("订单回推处理队列已满")
+
+// ErrCallbackNotFound 回推消息不存在
+var ErrCallbackNotFound = errors.New("订单回推消息不存在")
+
+// CallbackState 回推消息状态
+type CallbackState string
+
+const (
+	CallbackPending CallbackState = "pending" // 等待处理或等待重试
+	CallbackDead    CallbackState = "dead"    // 超过最大处理次数，需要人工处理或重新投递
+)
+
+// CallbackMessage 保存的订单回推
+type CallbackMessage struct {
+	ID        string        // 消息id，与回推的去重键相同
+	State     CallbackState // 状态
+	Params    []byte        // 回推参数，JSON
+	Raw       []byte        // 回推的原始内容
+	Attempts  int           // 已处理次数
+	LastError string        // 最近一次处理失败的错误
+	CreatedAt time.Time     // 创建时间
+	UpdatedAt time.Time     // 更新时间
+}
+
+// 根据已校验的回推创建消息
+func newCallbackMessage(callback *ServeHttpOrderHttpRequest) (CallbackMessage, error) {
+	params, err := gojson.Marshal(callback.Params())
+	if err != nil {
+		return CallbackMessage{}, err
+	}
+	now := time.Now()
+	return CallbackMessage{
+		ID:        callback.DedupKey(),
+		State:     CallbackPending,
+		Params:    params,
+		Raw:       callback.Raw,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// Callback 还原订单回推
+func (m CallbackMessage) Callback() (*ServeHttpOrderHttpRequest, error) {
+	callback := &ServeHttpOrderHttpRequest{}
+	if err := callback.decodeJSON(m.Params); err != nil {
+		return nil, fmt.Errorf("订单回推消息 %s 解析失败: %w", m.ID, err)
+	}
+	callback.Raw = m.Raw
+	return callback, nil
+}
+
+// CallbackStore 订单回推消息存储
+type CallbackStore interface {
+	// Save 新增或更新消息
+	Save(ctx context.Context, message CallbackMessage) error
+	// Get 获取消息，不存在时返回 ErrCallbackNotFound
+	Get(ctx context.Context, id string) (CallbackMessage, error)
+	// Delete 删除消息
+	Delete(ctx context.Context, id string) error
+	// List 按创建时间查询指定状态的消息，limit 为0时不限制
+	List(ctx context.Context, state CallbackState, limit int) ([]CallbackMessage, error)
+}
+
+// MemoryCallbackStore 内存回推消息存储，进程重启后丢失
+type MemoryCallbackStore struct {
+	mu       sync.RWMutex
+	messages map[string]CallbackMessage
+}
+
+// NewMemoryCallbackStore 创建内存回推消息存储
+func NewMemoryCallbackStore() *MemoryCallbackStore {
+	return &MemoryCallbackStore{messages: make(map[string]CallbackMessage)}
+}
+
+func (s *MemoryCallbackStore) Save(ctx context.Context, message CallbackMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if prev, ok := s.messages[message.ID]; ok {
+		message.CreatedAt = prev.CreatedAt
+	}
+	s.messages[message.ID] = message
+	return nil
+}
+
+func (s *MemoryCallbackStore) Get(ctx context.Context, id string) (CallbackMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	message, ok := s.messages[id]
+	if !ok {
+		return CallbackMessage{}, ErrCallbackNotFound
+	}
+	return message, nil
+}
+
+func (s *MemoryCallbackStore) Delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.messages, id)
+	return nil
+}
+
+func (s *MemoryCallbackStore) List(ctx context.Context, state CallbackState, limit int) ([]CallbackMessage, error) {
+	s.mu.RLock()
+	var messages []CallbackMessage
+	for _, message := range s.messages {
+		if message.State == state {
+			messages = append(messages, message)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(messages, func(i, j int) bool {
+		if !messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+		}
+		return messages[i].ID < messages[j].ID
+	})
+	if limit > 0 && limit < len(messages) {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
+
+// CallbackQueueConfig 回推异步处理配置
+type CallbackQueueConfig struct {
+	Handler     OrderCallbackFunc // 回推处理
+	Store       CallbackStore     // 回推消息存储，默认使用内存存储
+	Workers     int               // 同时处理的回推数量，默认4
+	QueueSize   int               // 队列长度，默认1024
+	MaxAttempts int               // 最大处理次数，超过后转为死信，默认5
+	Retry       RetryConfig       // 处理失败后的退避等待，MaxAttempts 不使用，默认首次1s、最大1m
+}
+
+// CallbackQueue 订单回推异步处理
+// 作为 OrderCallbackHandler 的处理函数使用：c.OrderCallbackHandler(queue.Enqueue)
+// 回推保存后立即回复美团，由本地队列的 worker 处理，失败后按退避时间重试，超过最大处理次数后转为死信
+// Start 时会重新处理存储中未完成的回推，同一个存储只能由一个进程处理
+// 处理函数和存储使用不会被 Close 取消的 context，处理函数需要自行控制超时
+type CallbackQueue struct {
+	config CallbackQueueConfig
+	jobs   chan CallbackMessage
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewCallbackQueue 创建回推异步处理
+func NewCallbackQueue(config CallbackQueueConfig) *CallbackQueue {
+	if config.Store == nil {
+		config.Store = NewMemoryCallbackStore()
+	}
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1024
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.Retry.InitialBackoff <= 0 {
+		config.Retry.InitialBackoff = time.Second
+	}
+	if config.Retry.MaxBackoff <= 0 {
+		config.Retry.MaxBackoff = time.Minute
+	}
+	config.Retry = config.Retry.withDefaults()
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CallbackQueue{
+		config: config,
+		jobs:   make(chan CallbackMessage, config.QueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Start 启动 worker，并重新投递存储中等待处理的回推，ctx 取消或调用 Close 后停止，只能调用一次
+func (q *CallbackQueue) Start(ctx context.Context) error {
+	pending, err := q.config.Store.List(ctx, CallbackPending, 0)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			q.cancel()
+		case <-q.ctx.Done():
+		}
+	}()
+
+	for i := 0; i < q.config.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		for _, message := range pending {
+			select {
+			case q.jobs <- message:
+			case <-q.ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close 停止处理并等待正在处理的回推完成，未完成的回推保留在存储中
+func (q *CallbackQueue) Close() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+// Enqueue 保存回推并加入队列，队列已满时返回 ErrCallbackQueueFull
+// 存储中已有相同的回推时不重复保存：等待处理的回推由队列继续处理，死信保留到调用 Redrive
+// 同一个回推并发调用时需要配合 OrderCallback.Dedup 使用
+func (q *CallbackQueue) Enqueue(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+	message, err := newCallbackMessage(callback)
+	if err != nil {
+		return err
+	}
+	if _, err = q.config.Store.Get(ctx, message.ID); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrCallbackNotFound) {
+		return err
+	}
+	if err = q.config.Store.Save(ctx, message); err != nil {
+		return err
+	}
+	select {
+	case q.jobs <- message:
+		return nil
+	default:
+		// 美团会重新推送，删除本次保存的回推
+		_ = q.config.Store.Delete(context.WithoutCancel(ctx), message.ID)
+		return ErrCallbackQueueFull
+	}
+}
+
+// DeadLetters 查询死信，limit 为0时不限制
+func (q *CallbackQueue) DeadLetters(ctx context.Context, limit int) ([]CallbackMessage, error) {
+	return q.config.Store.List(ctx, CallbackDead, limit)
+}
+
+// Redrive 重新投递死信，处理次数重新计算
+func (q *CallbackQueue) Redrive(ctx context.Context, id string) error {
+	message, err := q.config.Store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if message.State != CallbackDead {
+		return fmt.Errorf("%w: 订单回推消息 %s 不是死信", ErrInvalidRequest, id)
+	}
+	message.State = CallbackPending
+	message.Attempts = 0
+	message.LastError = ""
+	message.UpdatedAt = time.Now()
+	if err = q.config.Store.Save(ctx, message); err != nil {
+		return err
+	}
+	select {
+	case q.jobs <- message:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// worker 从队列获取回推并处理
+func (q *CallbackQueue) work() {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-q.ctx.Done():
+			return
+		case message := <-q.jobs:
+			q.process(message)
+		}
+	}
+}
+
+// 处理一个回推，失败时重试或转为死信
+// Close 等待正在处理的回推完成，处理和保存结果不使用会被 Close 取消的 q.ctx
+func (q *CallbackQueue) process(message CallbackMessage) {
+	ctx := context.WithoutCancel(q.ctx)
+	callback, err := message.Callback()
+	if err == nil && q.config.Handler != nil {
+		err = q.config.Handler(ctx, callback)
+	}
+	if err == nil {
+		_ = q.config.Store.Delete(ctx, message.ID)
+		return
+	}
+
+	message.Attempts++
+	message.LastError = err.Error()
+	message.UpdatedAt = time.Now()
+	if callback == nil || message.Attempts >= q.config.MaxAttempts {
+		message.State = CallbackDead
+		_ = q.config.Store.Save(ctx, message)
+		return
+	}
+	_ = q.config.Store.Save(ctx, message)
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		if sleepContext(q.ctx, q.config.Retry.backoff(message.Attempts)) != nil {
+			return
+		}
+		select {
+		case q.jobs <- message:
+		case <-q.ctx.Done():
+		}
+	}()
+}
@@ -0,0 +1,101 @@
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+)
+
+// 订单回推消息表
+const callbackQueueTable = LogTable + "_callback_queue"
+
+// SQLCallbackStore 基于 database/sql 的回推消息存储，支持 SQLite 和 PostgreSQL
+// 使用前需要调用 MigrateSQL 创建数据表，驱动由调用方引入
+type SQLCallbackStore struct {
+	db      *sql.DB
+	dialect SQLDialect
+}
+
+// NewSQLCallbackStore 创建数据库回推消息存储
+func NewSQLCallbackStore(db *sql.DB, dialect SQLDialect) (*SQLCallbackStore, error) {
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+	return &SQLCallbackStore{db: db, dialect: dialect}, nil
+}
+
+// Save 新增或更新消息，更新时保留创建时间
+func (s *SQLCallbackStore) Save(ctx context.Context, message CallbackMessage) error {
+	_, err := s.db.ExecContext(ctx, s.dialect.rebind(
+		"INSERT INTO "+callbackQueueTable+" (id, state, params, raw, attempts, last_error, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"+
+			" ON CONFLICT (id) DO UPDATE SET state = excluded.state, params = excluded.params, raw = excluded.raw,"+
+			" attempts = excluded.attempts, last_error = excluded.last_error, updated_at = excluded.updated_at",
+	), message.ID, string(message.State), string(message.Params), string(message.Raw), message.Attempts, message.LastError,
+		unixOrZero(message.CreatedAt), unixOrZero(message.UpdatedAt))
+	return err
+}
+
+func (s *SQLCallbackStore) Get(ctx context.Context, id string) (CallbackMessage, error) {
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(
+		"SELECT id, state, params, raw, attempts, last_error, created_at, updated_at FROM "+callbackQueueTable+" WHERE id = ?",
+	), id)
+	if err != nil {
+		return CallbackMessage{}, err
+	}
+	messages, err := scanCallbackMessages(rows)
+	if err != nil {
+		return CallbackMessage{}, err
+	}
+	if len(messages) == 0 {
+		return CallbackMessage{}, ErrCallbackNotFound
+	}
+	return messages[0], nil
+}
+
+func (s *SQLCallbackStore) Delete(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, s.dialect.rebind("DELETE FROM "+callbackQueueTable+" WHERE id = ?"), id)
+	return err
+}
+
+func (s *SQLCallbackStore) List(ctx context.Context, state CallbackState, limit int) ([]CallbackMessage, error) {
+	statement := "SELECT id, state, params, raw, attempts, last_error, created_at, updated_at FROM " + callbackQueueTable +
+		" WHERE state = ? ORDER BY created_at, id"
+	if limit > 0 {
+		statement += " LIMIT " + strconv.Itoa(limit)
+	}
+	rows, err := s.db.QueryContext(ctx, s.dialect.rebind(statement), string(state))
+	if err != nil {
+		return nil, err
+	}
+	return scanCallbackMessages(rows)
+}
+
+// 读取消息并关闭结果集
+func scanCallbackMessages(rows *sql.Rows) ([]CallbackMessage, error) {
+	defer rows.Close()
+
+	var messages []CallbackMessage
+	for rows.Next() {
+		var (
+			message              CallbackMessage
+			state, params, raw   string
+			createdAt, updatedAt int64
+		)
+		if err := rows.Scan(&message.ID, &state, &params, &raw, &message.Attempts, &message.LastError, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		message.State = CallbackState(state)
+		message.Params = []byte(params)
+		message.Raw = []byte(raw)
+		message.CreatedAt = timeOrZero(createdAt)
+		message.UpdatedAt = timeOrZero(updatedAt)
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+var _ CallbackStore = (*SQLCallbackStore)(nil)
+var _ CallbackStore = (*MemoryCallbackStore)(nil)
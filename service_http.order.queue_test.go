@@ -0,0 +1,274 @@
+package meituan
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// 测试使用的回推消息存储
+func testCallbackStores(t *testing.T) map[string]CallbackStore {
+	t.Helper()
+	stores := map[string]CallbackStore{"memory": NewMemoryCallbackStore()}
+	if newTestDB == nil {
+		return stores
+	}
+	store, err := NewSQLCallbackStore(openTestDB(t), SQLDialectSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stores["sql"] = store
+	return stores
+}
+
+// 测试使用的回推
+func testCallback(orderID string) *ServeHttpOrderHttpRequest {
+	return &ServeHttpOrderHttpRequest{Orderid: orderID, Status: "1", ModTime: "1700000000", Appkey: "appkey", Sign: "sign"}
+}
+
+// 等待条件满足
+func waitFor(t *testing.T, name string, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatalf("等待 %s 超时", name)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// 处理失败后的等待时间很短的队列配置
+func testQueueConfig(store CallbackStore, handler OrderCallbackFunc) CallbackQueueConfig {
+	return CallbackQueueConfig{
+		Handler:     handler,
+		Store:       store,
+		Workers:     2,
+		MaxAttempts: 3,
+		Retry:       RetryConfig{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+	}
+}
+
+func TestCallbackQueueRetry(t *testing.T) {
+	for name, store := range testCallbackStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var calls atomic.Int32
+			queue := NewCallbackQueue(testQueueConfig(store, func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+				if callback.Orderid != "1" {
+					t.Errorf("Orderid = %s", callback.Orderid)
+				}
+				if calls.Add(1) < 3 {
+					return errors.New("处理失败")
+				}
+				return nil
+			}))
+			ctx := context.Background()
+			if err := queue.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer queue.Close()
+
+			callback := testCallback("1")
+			if err := queue.Enqueue(ctx, callback); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "处理成功", func() bool {
+				_, err := store.Get(ctx, callback.DedupKey())
+				return errors.Is(err, ErrCallbackNotFound)
+			})
+			if got := calls.Load(); got != 3 {
+				t.Errorf("处理次数 %d, want 3", got)
+			}
+		})
+	}
+}
+
+func TestCallbackQueueDeadLetterAndRedrive(t *testing.T) {
+	for name, store := range testCallbackStores(t) {
+		t.Run(name, func(t *testing.T) {
+			var fail atomic.Bool
+			fail.Store(true)
+			queue := NewCallbackQueue(testQueueConfig(store, func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+				if fail.Load() {
+					return errors.New("处理失败")
+				}
+				return nil
+			}))
+			ctx := context.Background()
+			if err := queue.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer queue.Close()
+
+			callback := testCallback("1")
+			if err := queue.Enqueue(ctx, callback); err != nil {
+				t.Fatal(err)
+			}
+			var dead []CallbackMessage
+			waitFor(t, "转为死信", func() bool {
+				var err error
+				dead, err = queue.DeadLetters(ctx, 0)
+				return err == nil && len(dead) == 1
+			})
+			if dead[0].ID != callback.DedupKey() || dead[0].Attempts != 3 || dead[0].LastError != "处理失败" {
+				t.Errorf("死信 %+v", dead[0])
+			}
+
+			// 美团重新推送时保留死信
+			if err := queue.Enqueue(ctx, callback); err != nil {
+				t.Fatal(err)
+			}
+			message, err := store.Get(ctx, callback.DedupKey())
+			if err != nil || message.State != CallbackDead || message.Attempts != 3 {
+				t.Errorf("重新推送后的死信 %+v, %v", message, err)
+			}
+
+			// 只能重新投递死信
+			if err = queue.Redrive(ctx, "none"); !errors.Is(err, ErrCallbackNotFound) {
+				t.Errorf("Redrive 不存在的消息: %v", err)
+			}
+			fail.Store(false)
+			if err = queue.Redrive(ctx, callback.DedupKey()); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, "重新投递后处理成功", func() bool {
+				_, err := store.Get(ctx, callback.DedupKey())
+				return errors.Is(err, ErrCallbackNotFound)
+			})
+		})
+	}
+}
+
+func TestCallbackQueueFull(t *testing.T) {
+	for name, store := range testCallbackStores(t) {
+		t.Run(name, func(t *testing.T) {
+			config := testQueueConfig(store, nil)
+			config.QueueSize = 1
+			// 不启动 worker，队列中的回推不会被取出
+			queue := NewCallbackQueue(config)
+			ctx := context.Background()
+
+			dead := testCallback("dead")
+			message, err := newCallbackMessage(dead)
+			if err != nil {
+				t.Fatal(err)
+			}
+			message.State, message.Attempts, message.LastError = CallbackDead, 3, "处理失败"
+			if err = store.Save(ctx, message); err != nil {
+				t.Fatal(err)
+			}
+
+			if err = queue.Enqueue(ctx, testCallback("1")); err != nil {
+				t.Fatal(err)
+			}
+			// 相同的回推已在队列中
+			if err = queue.Enqueue(ctx, testCallback("1")); err != nil {
+				t.Errorf("重复推送: %v", err)
+			}
+			if err = queue.Enqueue(ctx, testCallback("2")); !errors.Is(err, ErrCallbackQueueFull) {
+				t.Errorf("队列已满: %v", err)
+			}
+			if _, err = store.Get(ctx, testCallback("2").DedupKey()); !errors.Is(err, ErrCallbackNotFound) {
+				t.Errorf("队列已满时不保留回推: %v", err)
+			}
+			if err = queue.Enqueue(ctx, dead); err != nil {
+				t.Errorf("重新推送死信: %v", err)
+			}
+
+			got, err := store.Get(ctx, message.ID)
+			if err != nil || got.State != CallbackDead || got.Attempts != 3 || got.LastError != "处理失败" {
+				t.Errorf("死信 %+v, %v", got, err)
+			}
+			if _, err = store.Get(ctx, testCallback("1").DedupKey()); err != nil {
+				t.Errorf("队列中的回推: %v", err)
+			}
+		})
+	}
+}
+
+func TestCallbackQueueCloseWaitsForHandler(t *testing.T) {
+	store := NewMemoryCallbackStore()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var handlerErr error
+	queue := NewCallbackQueue(testQueueConfig(store, func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+		close(started)
+		<-release
+		handlerErr = ctx.Err()
+		return ctx.Err()
+	}))
+	ctx := context.Background()
+	if err := queue.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	callback := testCallback("1")
+	if err := queue.Enqueue(ctx, callback); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		queue.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("Close 未等待正在处理的回推")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+
+	if handlerErr != nil {
+		t.Errorf("处理函数的 context 被取消: %v", handlerErr)
+	}
+	if _, err := store.Get(ctx, callback.DedupKey()); !errors.Is(err, ErrCallbackNotFound) {
+		t.Errorf("处理成功后未删除回推: %v", err)
+	}
+}
+
+func TestCallbackQueueStartRequeuesPending(t *testing.T) {
+	for name, store := range testCallbackStores(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for i := 0; i < 3; i++ {
+				message, err := newCallbackMessage(testCallback(strconv.Itoa(i)))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err = store.Save(ctx, message); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var (
+				mu        sync.Mutex
+				processed []string
+			)
+			queue := NewCallbackQueue(testQueueConfig(store, func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+				mu.Lock()
+				processed = append(processed, callback.Orderid)
+				mu.Unlock()
+				return nil
+			}))
+			if err := queue.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer queue.Close()
+			waitFor(t, "处理未完成的回推", func() bool {
+				pending, err := store.List(ctx, CallbackPending, 0)
+				return err == nil && len(pending) == 0
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if len(processed) != 3 {
+				t.Errorf("处理的回推 %v", processed)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package meituan
+
+import (
+	"context"
+	"encoding/json"
+	"go.dtapp.net/gorequest"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// 创建只用于校验回推的实例
+func newCallbackTestClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClient(&ClientConfig{Secret: "secret", AppKey: "appkey"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// 已签名的回推参数，status 和 actId 为数字
+func signedCallbackParams(c *Client) gorequest.Params {
+	params := gorequest.Params{
+		"orderid":       "1001",
+		"appkey":        "appkey",
+		"sid":           "sid1",
+		"status":        1,
+		"actId":         33,
+		"payPrice":      "25.80",
+		"modTime":       "1700000100",
+		"tradeTypeList": "[2,3]",
+	}
+	params.Set("sign", c.SignParams(params))
+	return params
+}
+
+// 检查解析后的回推字段
+func checkCallback(t *testing.T, callback ServeHttpOrderHttpRequest, raw string) {
+	t.Helper()
+	if callback.Orderid != "1001" || callback.Appkey != "appkey" || callback.Sid != "sid1" || callback.Status != "1" || callback.ActId != "33" || callback.PayPrice != "25.80" || callback.TradeTypeList != "[2,3]" {
+		t.Errorf("回推字段 %+v", callback)
+	}
+	if string(callback.Raw) != raw {
+		t.Errorf("Raw = %s, want %s", callback.Raw, raw)
+	}
+	order, err := callback.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != OrderStatusPaid || order.ActID != 33 || order.PayPrice != 2580 || len(order.TradeTypes) != 2 {
+		t.Errorf("订单 %+v", order)
+	}
+}
+
+func TestServeHttpOrderHttp(t *testing.T) {
+	c := newCallbackTestClient(t)
+	params := signedCallbackParams(c)
+
+	// JSON 数字
+	numeric, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JSON 字符串
+	strs := make(map[string]string, len(params))
+	form := url.Values{}
+	for k := range params {
+		strs[k] = SignValue(params.Get(k))
+		form.Set(k, strs[k])
+	}
+	text, err := json.Marshal(strs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		request func() *http.Request
+		raw     string
+	}{
+		{
+			name: "JSON 数字",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(numeric)))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			raw: string(numeric),
+		},
+		{
+			name: "JSON 字符串",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(text)))
+			},
+			raw: string(text),
+		},
+		{
+			name: "表单",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(form.Encode()))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+				return r
+			},
+			raw: form.Encode(),
+		},
+		{
+			name: "GET",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/callback?"+form.Encode(), nil)
+			},
+			raw: form.Encode(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callback, err := c.ServeHttpOrderHttp(context.Background(), httptest.NewRecorder(), tt.request())
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkCallback(t, callback, tt.raw)
+		})
+	}
+}
+
+func TestOrderCallbackHandler(t *testing.T) {
+	c := newCallbackTestClient(t)
+	params := signedCallbackParams(c)
+	body, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received *ServeHttpOrderHttpRequest
+	handler := c.OrderCallbackHandler(func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+		received = callback
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(body))))
+	if w.Code != http.StatusOK || received == nil {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	checkCallback(t, *received, string(body))
+
+	// 签名错误
+	params.Set("sign", "bad")
+	body, _ = json.Marshal(params)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(body))))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("签名错误 status = %d", w.Code)
+	}
+
+	// 内容格式错误
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader("{")))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("格式错误 status = %d", w.Code)
+	}
+}
+
+func TestOrderCallbackHandlerRejects(t *testing.T) {
+	c := newCallbackTestClient(t)
+	body, err := json.Marshal(signedCallbackParams(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	handler := c.OrderCallbackHandler(func(ctx context.Context, callback *ServeHttpOrderHttpRequest) error {
+		calls++
+		return nil
+	})
+
+	// 只支持 GET 和 POST
+	for _, method := range []string{http.MethodPut, http.MethodDelete, http.MethodPatch} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, "/callback", strings.NewReader(string(body))))
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, POST" {
+			t.Errorf("%s status = %d, Allow = %q", method, w.Code, w.Header().Get("Allow"))
+		}
+	}
+
+	// 超过最大长度
+	handler.MaxBodyBytes = int64(len(body)) - 1
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(body))))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("超过最大长度 status = %d, body = %s", w.Code, w.Body)
+	}
+	var reply ServeHttpOrderHttpResponse
+	if err = json.Unmarshal(w.Body.Bytes(), &reply); err != nil || reply.Errcode != 1 {
+		t.Errorf("回复 %s, %v", w.Body, err)
+	}
+	if calls != 0 {
+		t.Errorf("处理函数被调用 %d 次", calls)
+	}
+
+	// 刚好等于最大长度
+	handler.MaxBodyBytes = int64(len(body))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(string(body))))
+	if w.Code != http.StatusOK || calls != 1 {
+		t.Errorf("等于最大长度 status = %d, 处理 %d 次", w.Code, calls)
+	}
+}
@@ -0,0 +1,119 @@
+package meituan
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CPS开放接口签名使用的请求头
+const (
+	HeaderCaApp              = "S-Ca-App"
+	HeaderCaTimestamp        = "S-Ca-Timestamp"
+	HeaderCaSignature        = "S-Ca-Signature"
+	HeaderCaSignatureHeaders = "S-Ca-Signature-Headers"
+	HeaderContentMD5         = "Content-MD5"
+)
+
+// CpsContentMD5 请求内容的 md5 值进行 base64 编码，没有请求内容时为空字符串
+func CpsContentMD5(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	sum := md5.Sum(body)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// CpsStringToSign CPS开放接口待签名字符串
+// HTTPMethod + "\n" + Content-MD5 + "\n" + Headers + Url
+// Headers 为参与签名的请求头按名称升序排列的 名称:值\n
+// Url 为请求路径，有查询参数时拼接按参数名升序排列的 ?k=v&k=v
+func CpsStringToSign(method string, contentMD5 string, headers map[string]string, path string, query url.Values) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToUpper(method))
+	sb.WriteString("\n")
+	sb.WriteString(contentMD5)
+	sb.WriteString("\n")
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteString(":")
+		sb.WriteString(headers[name])
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(path)
+	if len(query) > 0 {
+		keys := make([]string, 0, len(query))
+		for k := range query {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, k := range keys {
+			v := query.Get(k)
+			if v == "" {
+				pairs = append(pairs, k)
+			} else {
+				pairs = append(pairs, k+"="+v)
+			}
+		}
+		sb.WriteString("?")
+		sb.WriteString(strings.Join(pairs, "&"))
+	}
+	return sb.String()
+}
+
+// CpsSignature 使用 HmacSHA256 计算签名并进行 base64 编码
+func CpsSignature(secret string, stringToSign string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// 为CPS开放接口请求添加签名请求头，timestamp 为13位时间戳(毫秒级)
+func signCpsRequest(req *http.Request, body []byte, appKey string, secret string, timestamp int64) {
+	signHeaders := map[string]string{
+		HeaderCaApp:       appKey,
+		HeaderCaTimestamp: strconv.FormatInt(timestamp, 10),
+	}
+	contentMD5 := CpsContentMD5(body)
+
+	stringToSign := CpsStringToSign(req.Method, contentMD5, signHeaders, req.URL.Path, req.URL.Query())
+
+	for name, value := range signHeaders {
+		req.Header.Set(name, value)
+	}
+	if contentMD5 != "" {
+		req.Header.Set(HeaderContentMD5, contentMD5)
+	}
+	req.Header.Set(HeaderCaSignatureHeaders, HeaderCaApp+","+HeaderCaTimestamp)
+	req.Header.Set(HeaderCaSignature, CpsSignature(secret, stringToSign))
+}
+
+// VerifyCpsRequest 校验CPS开放接口请求的签名请求头，可用于本地模拟服务
+func VerifyCpsRequest(req *http.Request, body []byte, secret string) bool {
+	contentMD5 := req.Header.Get(HeaderContentMD5)
+	if contentMD5 != CpsContentMD5(body) {
+		return false
+	}
+	signHeaders := make(map[string]string)
+	for _, name := range strings.Split(req.Header.Get(HeaderCaSignatureHeaders), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			signHeaders[name] = req.Header.Get(name)
+		}
+	}
+	stringToSign := CpsStringToSign(req.Method, contentMD5, signHeaders, req.URL.Path, req.URL.Query())
+	return hmac.Equal([]byte(req.Header.Get(HeaderCaSignature)), []byte(CpsSignature(secret, stringToSign)))
+}
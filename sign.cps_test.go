@@ -0,0 +1,71 @@
+package meituan
+
+import (
+	"context"
+	"go.dtapp.net/gorequest"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCpsContentMD5(t *testing.T) {
+	if got := CpsContentMD5([]byte("hello world")); got != "XrY7u+Ae7tCTyyK7j1rNww==" {
+		t.Errorf("CpsContentMD5 = %q", got)
+	}
+	if got := CpsContentMD5(nil); got != "" {
+		t.Errorf("CpsContentMD5(nil) = %q", got)
+	}
+}
+
+func TestCpsStringToSign(t *testing.T) {
+	got := CpsStringToSign(
+		"post",
+		"XrY7u+Ae7tCTyyK7j1rNww==",
+		map[string]string{HeaderCaTimestamp: "1700000000000", HeaderCaApp: "appkey"},
+		"/cps_open/common/api/v1/get_referral_link",
+		url.Values{"c": {"x"}, "a": {"1"}, "b": {""}},
+	)
+	want := "POST\nXrY7u+Ae7tCTyyK7j1rNww==\nS-Ca-App:appkey\nS-Ca-Timestamp:1700000000000\n/cps_open/common/api/v1/get_referral_link?a=1&b&c=x"
+	if got != want {
+		t.Errorf("CpsStringToSign = %q, want %q", got, want)
+	}
+	if got := CpsStringToSign("GET", "", nil, "/path", nil); got != "GET\n\n/path" {
+		t.Errorf("CpsStringToSign 无请求头和参数 = %q", got)
+	}
+}
+
+func TestCpsSignature(t *testing.T) {
+	s := "POST\nXrY7u+Ae7tCTyyK7j1rNww==\nS-Ca-App:appkey\nS-Ca-Timestamp:1700000000000\n/cps_open/common/api/v1/get_referral_link?a=1&b&c=x"
+	if got := CpsSignature("secret", s); got != "P2TqJ8UVT0t51JA9E3sR02+U7O/0/mN+F7zpukSHGMY=" {
+		t.Errorf("CpsSignature = %q", got)
+	}
+}
+
+func TestCpsOpenGetReferralLinkSigned(t *testing.T) {
+	var verified bool
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		verified = VerifyCpsRequest(r, body, "secret") && r.Header.Get(HeaderCaApp) == "appkey"
+		if !verified {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"code":401,"message":"签名验证失败"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"data":"https://example.com/link"}`))
+	}))
+
+	result, err := c.CpsOpenGetReferralLink(context.Background(), gorequest.Params{"actId": 1, "sid": "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verified || result.Result.Data != "https://example.com/link" {
+		t.Errorf("verified = %v, data = %q", verified, result.Result.Data)
+	}
+
+	// 秘钥不一致时服务端校验失败
+	c.SetSecret("other")
+	if _, err = c.CpsOpenGetReferralLink(context.Background(), gorequest.Params{"actId": 1}); !IsSignError(err) {
+		t.Errorf("错误秘钥: %v", err)
+	}
+}
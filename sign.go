@@ -3,30 +3,136 @@ package meituan
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/subtle"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"go.dtapp.net/gorequest"
-	"go.dtapp.net/gostring"
-	"io"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// 签名(sign)生成逻辑（新版）
+// Signer 签名
+// params 中的 sign 字段不参与签名
+type Signer interface {
+	Sign(secret string, params gorequest.Params) string
+}
+
+// MD5Signer 签名(sign)生成逻辑（新版）
+// secret + 按参数名字典升序排列的 参数名+参数值 + secret，取 md5 小写十六进制
+// 参数值按 SignValue 规则转换为字符串
 // https://union.meituan.com/v2/apiDetail?id=27
-func (c *Client) getSign(Secret string, param gorequest.Params) string {
+type MD5Signer struct{}
+
+func (MD5Signer) Sign(secret string, params gorequest.Params) string {
 	// 参数按照参数名的字典升序排列
-	var keys []string
-	for k := range param {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "sign" {
+			continue
+		}
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
-	signStr := bytes.NewBufferString(Secret)
+	signStr := bytes.NewBufferString(secret)
 	for _, k := range keys {
 		signStr.WriteString(k)
-		signStr.WriteString(gostring.GetString(param.Get(k)))
+		signStr.WriteString(SignValue(params.Get(k)))
 	}
-	signStr.WriteString(Secret)
+	signStr.WriteString(secret)
 	// md5加密
-	has := md5.New()
-	io.Copy(has, signStr)
-	return hex.EncodeToString(has.Sum(nil))
+	sum := md5.Sum(signStr.Bytes())
+	return hex.EncodeToString(sum[:])
+}
+
+// SignValue 参数值转换为签名和请求使用的字符串
+//   - nil 为空字符串
+//   - 字符串、[]byte 原样使用
+//   - 布尔值为 true/false
+//   - 整数为十进制
+//   - 浮点数为不带指数的最短十进制，例如 0.1、12.5、100
+//   - time.Time 为10位时间戳(秒级)
+//   - 实现了 fmt.Stringer 的值使用 String()
+//   - 切片、数组、map、结构体为 JSON，map 的键按字典升序排列
+func SignValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.FormatInt(int64(v), 10)
+	case int8:
+		return strconv.FormatInt(int64(v), 10)
+	case int16:
+		return strconv.FormatInt(int64(v), 10)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case time.Time:
+		return strconv.FormatInt(v.Unix(), 10)
+	case fmt.Stringer:
+		return v.String()
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return ""
+		}
+		return SignValue(rv.Elem().Interface())
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	}
+	// 切片、数组、map、结构体使用标准库转换为 JSON，保证 map 的键有序
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// SignParams 使用配置的签名方式计算签名
+func (c *Client) SignParams(params gorequest.Params) string {
+	return c.signer.Sign(c.GetSecret(), params)
+}
+
+// VerifySign 校验签名是否正确
+func (c *Client) VerifySign(params gorequest.Params, sign string) bool {
+	expected := c.SignParams(params)
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(sign))) == 1
 }
@@ -0,0 +1,151 @@
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 数据库结构迁移，按方言分目录，文件名以版本号开头
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+// 已执行的迁移记录表
+const migrationTable = LogTable + "_schema_migrations"
+
+// SQLDialect 数据库方言
+type SQLDialect int
+
+const (
+	SQLDialectSQLite   SQLDialect = iota + 1 // SQLite
+	SQLDialectPostgres                       // PostgreSQL
+)
+
+func (d SQLDialect) String() string {
+	switch d {
+	case SQLDialectSQLite:
+		return "sqlite"
+	case SQLDialectPostgres:
+		return "postgres"
+	default:
+		return "SQLDialect(" + strconv.Itoa(int(d)) + ")"
+	}
+}
+
+func (d SQLDialect) validate() error {
+	switch d {
+	case SQLDialectSQLite, SQLDialectPostgres:
+		return nil
+	default:
+		return fmt.Errorf("不支持的数据库方言: %s", d)
+	}
+}
+
+// 将 ? 占位符转换为方言的占位符
+func (d SQLDialect) rebind(query string) string {
+	if d != SQLDialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// MigrateSQL 执行方言对应的数据库结构迁移，已执行的迁移会跳过
+func MigrateSQL(ctx context.Context, db *sql.DB, dialect SQLDialect) error {
+	if err := dialect.validate(); err != nil {
+		return err
+	}
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationTable+" (version TEXT NOT NULL PRIMARY KEY, applied_at BIGINT NOT NULL)"); err != nil {
+		return fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+
+	dir := path.Join("migrations", dialect.String())
+	entries, err := fs.ReadDir(migrationFS, dir)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(name, ".sql")
+		script, err := fs.ReadFile(migrationFS, path.Join(dir, name))
+		if err != nil {
+			return err
+		}
+		if err = migrate(ctx, db, dialect, version, string(script)); err != nil {
+			return fmt.Errorf("执行迁移 %s 失败: %w", version, err)
+		}
+	}
+	return nil
+}
+
+// 在事务中执行一个迁移并记录版本
+func migrate(ctx context.Context, db *sql.DB, dialect SQLDialect, version string, script string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // nolint
+
+	var count int
+	if err = tx.QueryRowContext(ctx, dialect.rebind("SELECT COUNT(*) FROM "+migrationTable+" WHERE version = ?"), version).Scan(&count); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	// 逐条执行，部分驱动不支持一次执行多条语句
+	for _, statement := range strings.Split(script, ";") {
+		if strings.TrimSpace(statement) == "" {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, dialect.rebind("INSERT INTO "+migrationTable+" (version, applied_at) VALUES (?, ?)"), version, time.Now().Unix()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// 时间保存为10位时间戳，零值保存为0
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func timeOrZero(ts int64) time.Time {
+	if ts == 0 {
+		return time.Time{}
+	}
+	return time.Unix(ts, 0)
+}
@@ -0,0 +1,79 @@
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// 创建执行过迁移的测试数据库，SQLite 驱动需要 cgo，没有 cgo 时为 nil
+var newTestDB func(t *testing.T) *sql.DB
+
+// 创建测试数据库，没有 cgo 时跳过测试
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	if newTestDB == nil {
+		t.Skip("SQLite 驱动需要 cgo")
+	}
+	return newTestDB(t)
+}
+
+func TestMigrateSQL(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// 重复执行时跳过已执行的迁移
+	if err := MigrateSQL(ctx, db, SQLDialectSQLite); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+migrationTable).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := migrationFS.ReadDir("migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != len(entries) {
+		t.Errorf("迁移记录 %d 条, want %d", count, len(entries))
+	}
+	for _, table := range []string{orderTable, migrationTable} {
+		if _, err = db.ExecContext(ctx, "SELECT * FROM "+table+" LIMIT 1"); err != nil {
+			t.Errorf("数据表 %s: %v", table, err)
+		}
+	}
+
+	if err = MigrateSQL(ctx, db, SQLDialect(0)); err == nil {
+		t.Error("不支持的方言应返回错误")
+	}
+}
+
+func TestSQLDialectRebind(t *testing.T) {
+	query := "SELECT * FROM t WHERE a = ? AND b IN (?, ?)"
+	if got := SQLDialectSQLite.rebind(query); got != query {
+		t.Errorf("sqlite rebind = %q", got)
+	}
+	if got, want := SQLDialectPostgres.rebind(query), "SELECT * FROM t WHERE a = $1 AND b IN ($2, $3)"; got != want {
+		t.Errorf("postgres rebind = %q, want %q", got, want)
+	}
+}
+
+func TestSQLDialectMigrations(t *testing.T) {
+	// 每个方言的迁移版本一致
+	sqlite, err := migrationFS.ReadDir("migrations/sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	postgres, err := migrationFS.ReadDir("migrations/postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sqlite) != len(postgres) {
+		t.Fatalf("sqlite %d 个迁移, postgres %d 个迁移", len(sqlite), len(postgres))
+	}
+	for i := range sqlite {
+		if sqlite[i].Name() != postgres[i].Name() {
+			t.Errorf("迁移 %s 与 %s 不一致", sqlite[i].Name(), postgres[i].Name())
+		}
+	}
+}
@@ -0,0 +1,30 @@
+//go:build cgo
+
+package meituan
+
+import (
+	"context"
+	"database/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"testing"
+)
+
+func init() {
+	newTestDB = openSQLiteTestDB
+}
+
+// 创建内存 SQLite 数据库并执行迁移
+func openSQLiteTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 内存数据库每个连接相互独立
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	if err = MigrateSQL(context.Background(), db, SQLDialectSQLite); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}